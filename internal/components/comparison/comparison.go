@@ -0,0 +1,139 @@
+package comparison
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/vitor-mariano/regex-tui/internal/styles"
+	"github.com/vitor-mariano/regex-tui/pkg/components/regexview"
+	"github.com/vitor-mariano/regex-tui/pkg/regex"
+)
+
+const comparisonHSpacing = 4
+
+// Model renders the subject matched by another engine, highlighting the
+// matches that differ from the ones of the primary view.
+type Model struct {
+	primary       *regexview.Model
+	view          *regexview.Model
+	engine        string
+	err           error
+	width, height int
+}
+
+func New(primary *regexview.Model) *Model {
+	return &Model{primary: primary}
+}
+
+// Engine returns the engine compared with, or "" when the comparison is
+// disabled.
+func (m *Model) Engine() string {
+	return m.engine
+}
+
+// SetEngine compares with the given engine, or disables the comparison when
+// engine is "".
+func (m *Model) SetEngine(engine string) {
+	m.engine = engine
+}
+
+// Cycle compares with the next engine other than the primary one, disabling
+// the comparison after the last engine.
+func (m *Model) Cycle() {
+	var names []string
+	for _, engine := range regex.Engines() {
+		if engine.Name != m.primary.Engine() {
+			names = append(names, engine.Name)
+		}
+	}
+
+	next := ""
+	for i, name := range names {
+		if m.engine == "" {
+			next = name
+			break
+		}
+		if name == m.engine && i+1 < len(names) {
+			next = names[i+1]
+			break
+		}
+	}
+
+	m.engine = next
+}
+
+// SetExpression evaluates expression with the compared engine, using the
+// settings and value of the primary view.
+func (m *Model) SetExpression(expression string) {
+	prev := m.view
+	m.view, m.err = nil, nil
+	m.primary.SetReference(nil)
+	if m.engine == "" {
+		return
+	}
+
+	view := m.primary.WithEngine(m.engine)
+	if m.err = view.SetExpression(expression); m.err != nil {
+		return
+	}
+
+	// The view is rebuilt on every update, but its matches only change with
+	// the expression or the settings of the primary view.
+	view.KeepResults(prev)
+
+	view.SetSize(m.width-comparisonHSpacing-1, m.height)
+	view.SetReference(m.primary)
+	m.primary.SetReference(view)
+	m.view = view
+}
+
+func (m *Model) statusView() string {
+	if m.err != nil {
+		return m.engine
+	}
+
+	status := fmt.Sprintf("%s: %s", m.engine, m.view.Status())
+
+	// Each side counts the matches the other one does not share.
+	var differences []string
+	if n := m.primary.Differences(); n > 0 {
+		differences = append(differences, fmt.Sprintf("%d only in %s", n, m.primary.Engine()))
+	}
+	if n := m.view.Differences(); n > 0 {
+		differences = append(differences, fmt.Sprintf("%d only in %s", n, m.engine))
+	}
+
+	if len(differences) == 0 {
+		return status + " · same matches"
+	}
+
+	return status + " · " + strings.Join(differences, ", ")
+}
+
+func (m *Model) View() string {
+	s := &styles.InputContainerStyle
+	var v string
+	if m.err != nil {
+		s = &styles.ErrorInputContainerStyle
+		v = lipgloss.Place(
+			m.width-comparisonHSpacing-1, m.height,
+			lipgloss.Left, lipgloss.Top,
+			wordwrap.String(m.err.Error(), m.width-comparisonHSpacing-1),
+		)
+	} else {
+		v = m.view.View()
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		styles.HintTextStyle.Render(m.statusView()),
+		s.Width(m.width).Render(v),
+	)
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
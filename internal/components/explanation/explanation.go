@@ -0,0 +1,97 @@
+package explanation
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/vitor-mariano/regex-tui/internal/styles"
+	"github.com/vitor-mariano/regex-tui/pkg/components/regexview"
+	"github.com/vitor-mariano/regex-tui/pkg/regex/explain"
+	"github.com/vitor-mariano/regex-tui/pkg/utils"
+)
+
+const explanationHSpacing = 4
+
+// Model renders the current expression as a tree of plain-word descriptions.
+type Model struct {
+	view          *regexview.Model
+	expression    string
+	isOpen        bool
+	width, height int
+}
+
+func New(view *regexview.Model) *Model {
+	return &Model{view: view}
+}
+
+func (m *Model) IsOpen() bool {
+	return m.isOpen
+}
+
+func (m *Model) Toggle() {
+	m.isOpen = !m.isOpen
+}
+
+func (m *Model) SetExpression(expression string) {
+	m.expression = expression
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m *Model) View() string {
+	width := m.width - explanationHSpacing - 1
+	height := max(m.height, 1)
+
+	s := &styles.InputContainerStyle
+	var content string
+	nodes, err := m.view.Explain(m.expression)
+	switch {
+	case err != nil:
+		s = &styles.ErrorInputContainerStyle
+		content = wordwrap.String(err.Error(), width)
+	case len(nodes) == 0:
+		content = "empty expression, matches everywhere"
+	default:
+		var lines []string
+		render(&lines, nodes, "", true)
+		lines = utils.ClipLines(lines, height)
+		for i, line := range lines {
+			lines[i] = truncate.StringWithTail(line, uint(width), "…")
+		}
+		content = strings.Join(lines, "\n")
+	}
+
+	return s.Width(m.width).Render(lipgloss.Place(
+		width, height,
+		lipgloss.Left, lipgloss.Top,
+		content,
+	))
+}
+
+// render appends a line per node, drawing branches below the top level.
+// Nodes with a single leaf child are described on one line.
+func render(lines *[]string, nodes []*explain.Node, prefix string, top bool) {
+	for i, node := range nodes {
+		branch, indent := "├─ ", "│  "
+		if i == len(nodes)-1 {
+			branch, indent = "└─ ", "   "
+		}
+		if top {
+			branch, indent = "", ""
+		}
+
+		description, children := node.Description, node.Children
+		for len(children) == 1 && len(children[0].Children) == 0 {
+			description += " → " + children[0].Description
+			children = nil
+		}
+
+		*lines = append(*lines, prefix+branch+description)
+		render(lines, children, prefix+indent, false)
+	}
+}
@@ -1,10 +1,32 @@
 package expression
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
 	"github.com/vitor-mariano/regex-tui/internal/styles"
 	"github.com/vitor-mariano/regex-tui/pkg/components/regexview"
+	"github.com/vitor-mariano/regex-tui/pkg/regex"
+)
+
+var (
+	tokenStyles = map[regex.TokenKind]lipgloss.Style{
+		regex.TokenGroup:       lipgloss.NewStyle().Foreground(lipgloss.Color("39")),
+		regex.TokenAlternation: lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Bold(true),
+		regex.TokenClass:       lipgloss.NewStyle().Foreground(lipgloss.Color("114")),
+		regex.TokenQuantifier:  lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+		regex.TokenAnchor:      lipgloss.NewStyle().Foreground(lipgloss.Color("170")),
+		regex.TokenEscape:      lipgloss.NewStyle().Foreground(lipgloss.Color("180")),
+		regex.TokenComment:     lipgloss.NewStyle().Foreground(styles.MutedColor),
+	}
+	pairStyle   = lipgloss.NewStyle().Background(lipgloss.Color("238")).Bold(true)
+	cursorStyle = lipgloss.NewStyle().Foreground(styles.PrimaryColor).Reverse(true)
 )
 
 type Model struct {
@@ -53,12 +75,158 @@ func (m *Model) View() string {
 		s = &styles.FocusedInputContainerStyle
 	}
 
-	return s.Width(m.width).Render(m.input.View())
+	v := s.Width(m.width).Render(m.inputView())
+	if m.input.Err != nil {
+		v = lipgloss.JoinVertical(lipgloss.Left, v, m.errorView())
+		if hint := m.featureHint(); hint != "" {
+			v = lipgloss.JoinVertical(lipgloss.Left, v, styles.HintTextStyle.Width(m.width).Render(hint))
+		}
+
+		return v
+	}
+
+	if quoted, ok := m.view.QuotedExpression(m.input.Value()); ok && quoted != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, v, styles.HintTextStyle.Render("regex: "+quoted))
+	}
+
+	for _, warning := range m.view.Warnings(m.input.Value()) {
+		v = lipgloss.JoinVertical(lipgloss.Left, v, styles.WarningTextStyle.Width(m.width).Render("⚠ "+warning.Message))
+	}
+
+	return v
+}
+
+// inputView renders the expression with syntax highlighting, highlighting
+// the bracket under or before the cursor along with its counterpart. The
+// view scrolls horizontally to keep the cursor visible.
+func (m *Model) inputView() string {
+	value := m.input.Value()
+	if _, literal := m.view.QuotedExpression(value); value == "" || literal {
+		return m.input.View()
+	}
+
+	tokens := regex.Tokenize(value)
+	tokenAt := make([]int, len(value))
+	for i, token := range tokens {
+		for b := token.Start; b < token.End; b++ {
+			tokenAt[b] = i
+		}
+	}
+
+	runes := []rune(value)
+	offsets := make([]int, 0, len(runes)+1)
+	for b := range value {
+		offsets = append(offsets, b)
+	}
+	offsets = append(offsets, len(value))
+
+	pos := m.input.Position()
+	paired := map[int]bool{}
+	for _, b := range []int{offsets[pos], offsets[pos] - 1} {
+		if b < 0 || b >= len(value) {
+			continue
+		}
+		if token := tokens[tokenAt[b]]; token.Pair >= 0 {
+			paired[tokenAt[b]] = true
+			paired[token.Pair] = true
+			break
+		}
+	}
+
+	// Fit the runes around the cursor, which takes a cell at the end.
+	width := m.input.Width() + 1
+	start, end, used := pos, pos, 1
+	if pos < len(runes) {
+		end, used = pos+1, lipgloss.Width(string(runes[pos]))
+	}
+	for start > 0 && used+lipgloss.Width(string(runes[start-1])) <= width {
+		start--
+		used += lipgloss.Width(string(runes[start]))
+	}
+	for end < len(runes) && used+lipgloss.Width(string(runes[end])) <= width {
+		used += lipgloss.Width(string(runes[end]))
+		end++
+	}
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		token := tokenAt[offsets[i]]
+		s := tokenStyles[tokens[token].Kind]
+		switch {
+		case i == pos && m.input.Focused():
+			s = cursorStyle
+		case paired[token]:
+			s = s.Inherit(pairStyle)
+		}
+
+		b.WriteString(s.Render(string(runes[i])))
+	}
+	if pos == len(runes) && m.input.Focused() {
+		b.WriteString(cursorStyle.Render(" "))
+	}
+
+	return b.String()
+}
+
+// errorView renders the error message below the input, preceded by a caret
+// pointing at the offending offset when it is known. Expressions wider than
+// the input are excerpted around the offset.
+func (m *Model) errorView() string {
+	var syntaxErr *regex.SyntaxError
+	if !errors.As(m.input.Err, &syntaxErr) || syntaxErr.Offset < 0 {
+		return styles.ErrorTextStyle.Render(m.input.Err.Error())
+	}
+
+	value := m.input.Value()
+	offset := min(syntaxErr.Offset, len(value))
+	excerpt := ""
+	if width := m.input.Width(); lipgloss.Width(value) > width {
+		start := max(offset-width/2, 0)
+		for start > 0 && !utf8.RuneStart(value[start]) {
+			start--
+		}
+
+		value = value[start:]
+		offset -= start
+		excerpt = truncate.String(value, uint(width)) + "\n"
+	}
+
+	caret := strings.Repeat(" ", lipgloss.Width(value[:offset])) + "^"
+
+	return styles.ErrorTextStyle.Render(excerpt + caret + "\n" + syntaxErr.Error())
+}
+
+// featureHint suggests switching engines when the expression uses features
+// the selected engine does not support.
+func (m *Model) featureHint() string {
+	unsupported := m.view.UnsupportedFeatures(m.input.Value())
+	if unsupported == 0 {
+		return ""
+	}
+
+	verb := "are"
+	if unsupported == regex.Lookahead || unsupported == regex.Lookbehind {
+		verb = "is"
+	}
+
+	hint := fmt.Sprintf("%s %s not supported by %s", strings.Join(unsupported.Names(), " and "), verb, m.view.Engine())
+	if engines := regex.EnginesSupporting(unsupported); len(engines) > 0 {
+		hint += fmt.Sprintf(" — switch to %s (ctrl+p)", engines[0].Name)
+	}
+
+	return hint
+}
+
+// Height returns the number of lines rendered by View.
+func (m *Model) Height() int {
+	return lipgloss.Height(m.View())
 }
 
 func (m *Model) SetWidth(width int) {
+	const inputHSpacing = 4
+
 	m.width = width
-	m.input.SetWidth(width)
+	m.input.SetWidth(width - inputHSpacing - 1)
 }
 
 func (m *Model) GetInput() *textinput.Model {
@@ -0,0 +1,117 @@
+package inspector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"charm.land/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/vitor-mariano/regex-tui/internal/styles"
+	"github.com/vitor-mariano/regex-tui/pkg/components/regexview"
+	"github.com/vitor-mariano/regex-tui/pkg/utils"
+)
+
+const inspectorHSpacing = 4
+
+// Model details the focused match of a view: its text, location and capture
+// groups.
+type Model struct {
+	view          *regexview.Model
+	isOpen        bool
+	width, height int
+}
+
+func New(view *regexview.Model) *Model {
+	return &Model{view: view}
+}
+
+func (m *Model) IsOpen() bool {
+	return m.isOpen
+}
+
+func (m *Model) Toggle() {
+	m.isOpen = !m.isOpen
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m *Model) View() string {
+	width := m.width - inspectorHSpacing - 1
+	height := max(m.height, 1)
+
+	s := &styles.InputContainerStyle
+	var content string
+	lines, err := m.lines()
+	if err != nil {
+		s = &styles.ErrorInputContainerStyle
+		content = wordwrap.String(err.Error(), width)
+	} else {
+		lines = utils.ClipLines(lines, height)
+		for i, line := range lines {
+			lines[i] = truncate.StringWithTail(line, uint(width), "…")
+		}
+		content = strings.Join(lines, "\n")
+	}
+
+	return s.Width(m.width).Render(lipgloss.Place(
+		width, height,
+		lipgloss.Left, lipgloss.Top,
+		content,
+	))
+}
+
+// lines describes the focused match, one detail per line.
+func (m *Model) lines() ([]string, error) {
+	matches, err := m.view.Matches()
+	if err != nil {
+		return nil, err
+	}
+
+	match, ok := m.view.FocusedMatch()
+	switch {
+	case len(matches) == 0:
+		return []string{"no matches"}, nil
+	case !ok:
+		return []string{"no match selected, press ctrl+n to step through matches"}, nil
+	}
+
+	value := m.view.Value()
+	lines := []string{
+		fmt.Sprintf("match %d of %d at line %d, column %d", match.Ordinal, len(matches), match.Line, match.Column),
+		"text   " + strconv.Quote(match.Text(value)),
+		fmt.Sprintf("bytes  %d-%d", match.Start, match.End),
+		fmt.Sprintf("runes  %d-%d", utf8.RuneCountInString(value[:match.Start]), utf8.RuneCountInString(value[:match.End])),
+	}
+
+	captures, ok, err := m.view.Captures(match)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, group := range match.Groups {
+		label := group.Label()
+		if !group.Matched {
+			lines = append(lines, fmt.Sprintf("group %s: did not participate", label))
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("group %s: %s at %d-%d", label, strconv.Quote(group.Text(value)), group.Start, group.End))
+
+		// Only repeated groups capture more than their last span.
+		if ok && i < len(captures) && len(captures[i]) > 1 {
+			spans := make([]string, len(captures[i]))
+			for j, c := range captures[i] {
+				spans[j] = fmt.Sprintf("%s at %d-%d", strconv.Quote(c.Text(value)), c.Start, c.End)
+			}
+			lines = append(lines, "  captures: "+strings.Join(spans, ", "))
+		}
+	}
+
+	return lines, nil
+}
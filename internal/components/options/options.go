@@ -6,24 +6,111 @@ import (
 	"charm.land/lipgloss/v2"
 	"github.com/vitor-mariano/regex-tui/internal/styles"
 	"github.com/vitor-mariano/regex-tui/pkg/components/multiselect"
+	"github.com/vitor-mariano/regex-tui/pkg/regex"
 )
 
 const (
 	GlobalOption      = "Global"
 	InsensitiveOption = "Insensitive"
-	Regexp2Option     = "Regexp2"
+	MultilineOption   = "Multiline"
+	DotAllOption      = "DotAll"
+	UngreedyOption    = "Ungreedy"
+	LiteralOption     = "Literal"
+	WhitespaceOption  = "Whitespace"
+	LineNumbersOption = "LineNumbers"
+
+	RightToLeftOption             = "RightToLeft"
+	ECMAScriptOption              = "ECMAScript"
+	ExplicitCaptureOption         = "ExplicitCapture"
+	IgnorePatternWhitespaceOption = "IgnorePatternWhitespace"
+	RE2CompatOption               = "RE2Compat"
 )
 
+var flagItems = []struct {
+	item string
+	flag regex.Flags
+}{
+	{InsensitiveOption, regex.Insensitive},
+	{MultilineOption, regex.Multiline},
+	{DotAllOption, regex.DotAll},
+	{UngreedyOption, regex.Ungreedy},
+	{LiteralOption, regex.Literal},
+	{RightToLeftOption, regex.RightToLeft},
+	{ECMAScriptOption, regex.ECMAScript},
+	{ExplicitCaptureOption, regex.ExplicitCapture},
+	{IgnorePatternWhitespaceOption, regex.IgnorePatternWhitespace},
+	{RE2CompatOption, regex.RE2Compat},
+}
+
 type Model struct {
 	options             *multiselect.Model
+	engine              string
 	isOptionsDialogOpen bool
 }
 
 func New() *Model {
-	return &Model{
-		options:             multiselect.New([]string{GlobalOption, InsensitiveOption, Regexp2Option}),
+	m := &Model{
+		options:             multiselect.New(nil),
+		engine:              regex.DefaultEngine,
 		isOptionsDialogOpen: false,
 	}
+	m.setItems()
+
+	return m
+}
+
+// Flag returns the expression flag toggled by item, if it is a flag option.
+func Flag(item string) (regex.Flags, bool) {
+	for _, fi := range flagItems {
+		if fi.item == item {
+			return fi.flag, true
+		}
+	}
+
+	return 0, false
+}
+
+// FlagOptions returns the items that enable the given flags.
+func FlagOptions(f regex.Flags) []string {
+	var selected []string
+	for _, fi := range flagItems {
+		if f.Has(fi.flag) {
+			selected = append(selected, fi.item)
+		}
+	}
+
+	return selected
+}
+
+// Engine returns the name of the engine selected by item, if it is an
+// engine option.
+func Engine(item string) (string, bool) {
+	engine, ok := regex.LookupEngine(item)
+	return engine.Name, ok
+}
+
+// setItems lists the global option, the common flags, the registered engines,
+// the flags specific to the selected engine and the display options.
+func (m *Model) setItems() {
+	engine, _ := regex.LookupEngine(m.engine)
+
+	items := []string{GlobalOption}
+	for _, fi := range flagItems {
+		if regex.CommonFlags.Has(fi.flag) {
+			items = append(items, fi.item)
+		}
+	}
+	for _, e := range regex.Engines() {
+		items = append(items, e.Name)
+	}
+	for _, fi := range flagItems {
+		if !regex.CommonFlags.Has(fi.flag) && engine.Flags.Has(fi.flag) {
+			items = append(items, fi.item)
+		}
+	}
+	items = append(items, WhitespaceOption, LineNumbersOption)
+
+	m.options.SetItems(items)
 }
 
 func (m *Model) IsOpen() bool {
@@ -35,8 +122,33 @@ func (m *Model) Open() {
 }
 
 func (m *Model) OnToggle(onToggle func(item string, selected bool)) {
-	m.options.OnToggle(onToggle)
-	m.options.SetSelected(GlobalOption)
+	m.options.OnToggle(func(item string, selected bool) {
+		engine, ok := Engine(item)
+		if !ok {
+			onToggle(item, selected)
+			return
+		}
+
+		// Engines behave as radio items: selecting one deselects the others
+		// and the selected one cannot be turned off.
+		if !selected {
+			if engine == m.engine {
+				m.options.SetSelected(engine)
+			}
+			return
+		}
+
+		m.engine = engine
+		for _, e := range regex.Engines() {
+			if e.Name != engine {
+				m.options.Deselect(e.Name)
+			}
+		}
+		m.setItems()
+
+		onToggle(item, selected)
+	})
+	m.options.SetSelected(GlobalOption, m.engine)
 }
 
 func (m *Model) SetSelected(items ...string) {
@@ -0,0 +1,57 @@
+package preview
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+	"github.com/vitor-mariano/regex-tui/internal/styles"
+	"github.com/vitor-mariano/regex-tui/pkg/components/regexview"
+	"github.com/vitor-mariano/regex-tui/pkg/utils"
+)
+
+// Model renders the subject after substituting matches with the replacement
+// template.
+type Model struct {
+	view          *regexview.Model
+	template      string
+	width, height int
+}
+
+func New(template string, view *regexview.Model) *Model {
+	return &Model{template: template, view: view}
+}
+
+func (m *Model) View() string {
+	const previewHSpacing = 4
+
+	width := m.width - previewHSpacing - 1
+	height := max(m.height, 1)
+
+	s := &styles.InputContainerStyle
+	value, err := m.view.Replace(m.template)
+	if err != nil {
+		s = &styles.ErrorInputContainerStyle
+		value = err.Error()
+	}
+
+	// Words longer than the pane are broken after wrapping at spaces.
+	lines := strings.Split(wrap.String(wordwrap.String(value, width), width), "\n")
+	lines = utils.ClipLines(lines, height)
+
+	return s.Width(m.width).Render(lipgloss.Place(
+		width, height,
+		lipgloss.Left, lipgloss.Top,
+		strings.Join(lines, "\n"),
+	))
+}
+
+func (m *Model) SetTemplate(template string) {
+	m.template = template
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
@@ -0,0 +1,55 @@
+package replacement
+
+import (
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"github.com/vitor-mariano/regex-tui/internal/styles"
+)
+
+type Model struct {
+	input textinput.Model
+	width int
+}
+
+func New(initialValue string) *Model {
+	m := textinput.New()
+	m.SetValue(initialValue)
+	m.SetVirtualCursor(true)
+	m.SetStyles(textinput.Styles{
+		Cursor: textinput.CursorStyle{
+			Color: styles.PrimaryColor,
+			Blink: true,
+		},
+	})
+	m.Prompt = ""
+	m.Placeholder = "Replacement"
+
+	return &Model{input: m}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	return cmd
+}
+
+func (m *Model) View() string {
+	s := &styles.InputContainerStyle
+	if m.input.Focused() {
+		s = &styles.FocusedInputContainerStyle
+	}
+
+	return s.Width(m.width).Render(m.input.View())
+}
+
+func (m *Model) SetWidth(width int) {
+	const inputHSpacing = 4
+
+	m.width = width
+	m.input.SetWidth(width - inputHSpacing - 1)
+}
+
+func (m *Model) GetInput() *textinput.Model {
+	return &m.input
+}
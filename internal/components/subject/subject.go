@@ -1,6 +1,8 @@
 package subject
 
 import (
+	"strings"
+
 	"charm.land/bubbles/v2/textarea"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -45,10 +47,33 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
 	m.input, cmd = m.input.Update(msg)
 	m.view.SetValue(m.input.Value())
+	m.view.SetCursor(m.cursorOffset())
 
 	return cmd
 }
 
+// cursorOffset returns the byte offset of the cursor in the value.
+func (m *Model) cursorOffset() int {
+	lines := strings.Split(m.input.Value(), "\n")
+	row := min(m.input.Line(), len(lines)-1)
+
+	offset := 0
+	for _, line := range lines[:row] {
+		offset += len(line) + 1
+	}
+
+	info := m.input.LineInfo()
+	col := info.StartColumn + info.ColumnOffset
+	for i := range lines[row] {
+		if col == 0 {
+			return offset + i
+		}
+		col--
+	}
+
+	return offset + len(lines[row])
+}
+
 func (m *Model) View() string {
 	s := &styles.InputContainerStyle
 	if m.input.Err != nil {
@@ -57,12 +82,15 @@ func (m *Model) View() string {
 		s = &styles.FocusedInputContainerStyle
 	}
 
-	v := m.input.View()
+	// The view stands in for the textarea, keeping matches highlighted while
+	// editing.
 	if !m.input.Focused() {
-		v = m.view.View()
+		m.view.SetCursor(-1)
+	} else {
+		m.view.SetCursor(m.cursorOffset())
 	}
 
-	return s.Width(m.width).Render(v)
+	return s.Width(m.width).Render(m.view.View())
 }
 
 func (m *Model) SetSize(width, height int) {
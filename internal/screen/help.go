@@ -6,7 +6,17 @@ type keyMap struct {
 	Exit          key.Binding
 	SwitchInput   key.Binding
 	ToggleOptions key.Binding
+	ToggleReplace key.Binding
+	CompareEngine key.Binding
+	Explain       key.Binding
 	OpenEditor    key.Binding
+	PageUp        key.Binding
+	PageDown      key.Binding
+	ScrollTop     key.Binding
+	ScrollBottom  key.Binding
+	NextMatch     key.Binding
+	PreviousMatch key.Binding
+	Inspect       key.Binding
 }
 
 var keys = keyMap{
@@ -22,19 +32,60 @@ var keys = keyMap{
 		key.WithKeys("ctrl+p"),
 		key.WithHelp("ctrl+p", "options"),
 	),
+	ToggleReplace: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "replace"),
+	),
+	CompareEngine: key.NewBinding(
+		key.WithKeys("ctrl+x"),
+		key.WithHelp("ctrl+x", "compare engines"),
+	),
+	Explain: key.NewBinding(
+		key.WithKeys("ctrl+g"),
+		key.WithHelp("ctrl+g", "explain"),
+	),
 	OpenEditor: key.NewBinding(
 		key.WithKeys("ctrl+o"),
 		key.WithHelp("ctrl+o", "edit text"),
 	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+		key.WithHelp("pgdn", "page down"),
+	),
+	ScrollTop: key.NewBinding(
+		key.WithKeys("ctrl+home"),
+		key.WithHelp("ctrl+home", "top"),
+	),
+	ScrollBottom: key.NewBinding(
+		key.WithKeys("ctrl+end"),
+		key.WithHelp("ctrl+end", "bottom"),
+	),
+	NextMatch: key.NewBinding(
+		key.WithKeys("ctrl+n"),
+		key.WithHelp("ctrl+n", "next match"),
+	),
+	PreviousMatch: key.NewBinding(
+		key.WithKeys("ctrl+b"),
+		key.WithHelp("ctrl+b", "previous match"),
+	),
+	Inspect: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("ctrl+t", "inspect match"),
+	),
 }
 
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Exit, k.SwitchInput},
-		{k.ToggleOptions, k.OpenEditor},
+		{k.ToggleOptions, k.ToggleReplace, k.CompareEngine, k.Explain, k.OpenEditor},
+		{k.NextMatch, k.PreviousMatch, k.Inspect, k.PageUp, k.PageDown, k.ScrollTop, k.ScrollBottom},
 	}
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Exit, k.SwitchInput, k.ToggleOptions, k.OpenEditor}
+	return []key.Binding{k.Exit, k.SwitchInput, k.ToggleOptions, k.ToggleReplace, k.CompareEngine, k.Explain, k.OpenEditor}
 }
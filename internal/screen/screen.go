@@ -3,49 +3,75 @@ package screen
 import (
 	"os"
 	"os/exec"
+	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/help"
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/vitor-mariano/regex-tui/internal/components/comparison"
+	"github.com/vitor-mariano/regex-tui/internal/components/explanation"
 	"github.com/vitor-mariano/regex-tui/internal/components/expression"
+	"github.com/vitor-mariano/regex-tui/internal/components/inspector"
 	"github.com/vitor-mariano/regex-tui/internal/components/options"
+	"github.com/vitor-mariano/regex-tui/internal/components/preview"
+	"github.com/vitor-mariano/regex-tui/internal/components/replacement"
 	"github.com/vitor-mariano/regex-tui/internal/components/subject"
+	"github.com/vitor-mariano/regex-tui/internal/styles"
 	"github.com/vitor-mariano/regex-tui/pkg/components/multiselect"
+	"github.com/vitor-mariano/regex-tui/pkg/regex"
 )
 
 type inputType int
 
 const (
 	inputTypeExpression inputType = iota
+	inputTypeReplacement
 	inputTypeSubject
 )
 
+// scrollStep is the number of rows scrolled by a mouse wheel event.
+const scrollStep = 3
+
 type editorFinishedMsg struct {
 	tempFile string
 	err      error
 }
 
 type Config struct {
-	InitialExpression string
-	InitialSubject    string
-	Global            bool
-	Insensitive       bool
-	Regexp2           bool
+	InitialExpression  string
+	InitialSubject     string
+	InitialReplacement string
+	Global             bool
+	ShowWhitespace     bool
+	ShowLineNumbers    bool
+	Flags              regex.Flags
+	Engine             string
+	CompareEngine      string
+	Timeout            time.Duration
 }
 
 type model struct {
-	expressionInput *expression.Model
-	subjectInput    *subject.Model
-	options         *options.Model
-	help            help.Model
+	expressionInput  *expression.Model
+	subjectInput     *subject.Model
+	replacementInput *replacement.Model
+	preview          *preview.Model
+	comparison       *comparison.Model
+	explanation      *explanation.Model
+	inspector        *inspector.Model
+	options          *options.Model
+	help             help.Model
 
 	focusedInputType inputType
+	replaceMode      bool
 	width, height    int
 }
 
 func New(config Config) model {
 	si := subject.New(config.InitialSubject, config.InitialExpression)
+	si.GetView().SetTimeout(config.Timeout)
 
 	ei := expression.New(config.InitialExpression, si.GetView())
 	ei.GetInput().Focus()
@@ -55,36 +81,61 @@ func New(config Config) model {
 		switch item {
 		case options.GlobalOption:
 			si.GetView().SetGlobal(selected)
-		case options.InsensitiveOption:
-			si.GetView().SetInsensitive(selected)
-		case options.Regexp2Option:
-			si.GetView().SetRegexp2(selected)
-			ei.GetInput().Err = si.GetView().SetRegexp2(selected)
-			// Force re-evaluation with the new engine.
-			si.SetExpression(ei.GetInput().Value())
+			return
+		case options.WhitespaceOption:
+			si.GetView().SetShowWhitespace(selected)
+			return
+		case options.LineNumbersOption:
+			si.GetView().SetShowLineNumbers(selected)
+			return
+		default:
+			if engine, ok := options.Engine(item); ok {
+				si.GetView().SetEngine(engine)
+			} else if flag, ok := options.Flag(item); ok {
+				si.GetView().SetFlag(flag, selected)
+			} else {
+				return
+			}
 		}
+
+		// Force re-evaluation of the current input with the new settings.
+		ei.GetInput().Err = si.SetExpression(ei.GetInput().Value())
 	})
 
 	var selectedOptions []string
 	if config.Global {
 		selectedOptions = append(selectedOptions, options.GlobalOption)
 	}
-	if config.Insensitive {
-		selectedOptions = append(selectedOptions, options.InsensitiveOption)
+	if config.ShowWhitespace {
+		selectedOptions = append(selectedOptions, options.WhitespaceOption)
+	}
+	if config.ShowLineNumbers {
+		selectedOptions = append(selectedOptions, options.LineNumbersOption)
 	}
-	if config.Regexp2 {
-		selectedOptions = append(selectedOptions, options.Regexp2Option)
+	selectedOptions = append(selectedOptions, options.FlagOptions(config.Flags)...)
+	if config.Engine != "" {
+		selectedOptions = append(selectedOptions, config.Engine)
 	}
 
 	if len(selectedOptions) > 0 {
 		d.SetSelected(selectedOptions...)
 	}
 
+	cmp := comparison.New(si.GetView())
+	cmp.SetEngine(config.CompareEngine)
+	cmp.SetExpression(config.InitialExpression)
+
 	return model{
-		expressionInput: ei,
-		subjectInput:    si,
-		options:         d,
-		help:            help.New(),
+		expressionInput:  ei,
+		subjectInput:     si,
+		replacementInput: replacement.New(config.InitialReplacement),
+		preview:          preview.New(config.InitialReplacement, si.GetView()),
+		comparison:       cmp,
+		explanation:      explanation.New(si.GetView()),
+		inspector:        inspector.New(si.GetView()),
+		options:          d,
+		help:             help.New(),
+		replaceMode:      config.InitialReplacement != "",
 	}
 }
 
@@ -93,12 +144,98 @@ func (m model) Init() tea.Cmd {
 }
 
 func (m *model) setSize(width, height int) {
-	const subjectVSpacing = 8
+	const (
+		subjectVSpacing = 6
+		replaceVSpacing = 5
+		compareVSpacing = 3
+		explainVSpacing = 2
+		inspectVSpacing = 2
+	)
 
 	m.width = width
 	m.height = height
 	m.expressionInput.SetWidth(width)
-	m.subjectInput.SetSize(width, height-subjectVSpacing)
+	m.replacementInput.SetWidth(width)
+	m.help.SetWidth(width)
+
+	// The expression grows to show errors below it.
+	available := height - subjectVSpacing - m.expressionInput.Height()
+	panes := 1
+	if m.replaceMode {
+		available -= replaceVSpacing
+		panes++
+	}
+	if m.comparison.Engine() != "" {
+		available -= compareVSpacing
+		panes++
+	}
+	if m.explanation.IsOpen() {
+		available -= explainVSpacing
+		panes++
+	}
+	if m.inspector.IsOpen() {
+		available -= inspectVSpacing
+		panes++
+	}
+
+	// The subject takes the remainder of splitting the space between panes.
+	paneHeight := max(available/panes, 1)
+	m.subjectInput.SetSize(width, max(available-(panes-1)*paneHeight, 1))
+	m.preview.SetSize(width, paneHeight)
+	m.comparison.SetSize(width, paneHeight)
+	m.explanation.SetSize(width, paneHeight)
+	m.inspector.SetSize(width, paneHeight)
+}
+
+func (m *model) focus(inputType inputType) tea.Cmd {
+	switch m.focusedInputType {
+	case inputTypeReplacement:
+		m.replacementInput.GetInput().Blur()
+	case inputTypeSubject:
+		m.subjectInput.GetInput().Blur()
+	default:
+		m.expressionInput.GetInput().Blur()
+	}
+
+	m.focusedInputType = inputType
+
+	switch inputType {
+	case inputTypeReplacement:
+		return m.replacementInput.GetInput().Focus()
+	case inputTypeSubject:
+		return m.subjectInput.GetInput().Focus()
+	default:
+		return m.expressionInput.GetInput().Focus()
+	}
+}
+
+func (m *model) nextInputType() inputType {
+	switch m.focusedInputType {
+	case inputTypeExpression:
+		if m.replaceMode {
+			return inputTypeReplacement
+		}
+		return inputTypeSubject
+	case inputTypeReplacement:
+		return inputTypeSubject
+	default:
+		return inputTypeExpression
+	}
+}
+
+func (m *model) toggleReplaceMode() tea.Cmd {
+	m.replaceMode = !m.replaceMode
+	m.setSize(m.width, m.height)
+
+	if m.replaceMode {
+		return m.focus(inputTypeReplacement)
+	}
+
+	if m.focusedInputType == inputTypeReplacement {
+		return m.focus(inputTypeExpression)
+	}
+
+	return nil
 }
 
 func findEditor() string {
@@ -141,25 +278,61 @@ func (m *model) openEditor() tea.Cmd {
 
 func (m *model) updateScreen(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, 0, 2)
+	view := m.subjectInput.GetView()
 
 	switch msg := msg.(type) {
+	case tea.MouseWheelMsg:
+		switch msg.Button {
+		case tea.MouseWheelUp:
+			view.ScrollBy(-scrollStep)
+		case tea.MouseWheelDown:
+			view.ScrollBy(scrollStep)
+		}
+		return nil
+
 	case tea.KeyPressMsg:
 		switch {
+		case key.Matches(msg, keys.PageUp):
+			view.PageUp()
+			return nil
+
+		case key.Matches(msg, keys.PageDown):
+			view.PageDown()
+			return nil
+
+		case key.Matches(msg, keys.ScrollTop):
+			view.ScrollToTop()
+			return nil
+
+		case key.Matches(msg, keys.ScrollBottom):
+			view.ScrollToBottom()
+			return nil
+
+		case key.Matches(msg, keys.NextMatch):
+			view.NextMatch()
+			return nil
+
+		case key.Matches(msg, keys.PreviousMatch):
+			view.PreviousMatch()
+			return nil
+
+		case key.Matches(msg, keys.Inspect):
+			m.inspector.Toggle()
+			return nil
+
 		case key.Matches(msg, keys.SwitchInput):
-			var cmd tea.Cmd
-			switch m.focusedInputType {
-			case inputTypeExpression:
-				m.focusedInputType = inputTypeSubject
-				m.expressionInput.GetInput().Blur()
-				cmd = m.subjectInput.GetInput().Focus()
-
-			case inputTypeSubject:
-				m.focusedInputType = inputTypeExpression
-				m.subjectInput.GetInput().Blur()
-				cmd = m.expressionInput.GetInput().Focus()
-			}
+			cmds = append(cmds, m.focus(m.nextInputType()))
+
+		case key.Matches(msg, keys.ToggleReplace):
+			return m.toggleReplaceMode()
+
+		case key.Matches(msg, keys.CompareEngine):
+			m.comparison.Cycle()
+			return nil
 
-			cmds = append(cmds, cmd)
+		case key.Matches(msg, keys.Explain):
+			m.explanation.Toggle()
+			return nil
 
 		case key.Matches(msg, keys.ToggleOptions):
 			if !m.options.IsOpen() {
@@ -171,9 +344,13 @@ func (m *model) updateScreen(msg tea.Msg) tea.Cmd {
 		}
 	}
 
-	if m.focusedInputType == inputTypeSubject {
+	switch m.focusedInputType {
+	case inputTypeSubject:
 		cmds = append(cmds, m.subjectInput.Update(msg))
-	} else {
+	case inputTypeReplacement:
+		cmds = append(cmds, m.replacementInput.Update(msg))
+		m.preview.SetTemplate(m.replacementInput.GetInput().Value())
+	default:
 		cmds = append(cmds, m.expressionInput.Update(msg))
 		m.subjectInput.SetExpression(m.expressionInput.GetInput().Value())
 	}
@@ -214,22 +391,58 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, m.updateScreen(msg))
 	}
 
+	m.setSize(m.width, m.height)
+	m.comparison.SetExpression(m.expressionInput.GetInput().Value())
+	m.explanation.SetExpression(m.expressionInput.GetInput().Value())
+
 	return m, tea.Batch(cmds...)
 }
 
+func (m model) statusView() string {
+	view := m.subjectInput.GetView()
+
+	status := view.Status()
+	if m.comparison.Engine() != "" {
+		status = view.Engine() + ": " + status
+	}
+	if scroll := view.ScrollStatus(); scroll != "" {
+		status = strings.TrimPrefix(status+" · "+scroll, " · ")
+	}
+
+	status = styles.HintTextStyle.Render(status)
+	if legend := view.Legend(); legend != "" {
+		status = truncate.StringWithTail(status+"  "+legend, uint(m.width), "…")
+	}
+
+	return status
+}
+
 func (m model) View() tea.View {
 	var helpKeyMap help.KeyMap = keys
 	if m.options.IsOpen() {
 		helpKeyMap = multiselect.Keys
 	}
 
-	baseLayer := lipgloss.NewLayer(lipgloss.JoinVertical(
-		lipgloss.Left,
-		title,
-		m.expressionInput.View(),
-		m.subjectInput.View(),
-		m.help.View(helpKeyMap),
-	))
+	sections := []string{title, m.expressionInput.View()}
+	if m.explanation.IsOpen() {
+		sections = append(sections, m.explanation.View())
+	}
+	if m.replaceMode {
+		sections = append(sections, m.replacementInput.View())
+	}
+	sections = append(sections, m.subjectInput.View(), m.statusView())
+	if m.replaceMode {
+		sections = append(sections, m.preview.View())
+	}
+	if m.comparison.Engine() != "" {
+		sections = append(sections, m.comparison.View())
+	}
+	if m.inspector.IsOpen() {
+		sections = append(sections, m.inspector.View())
+	}
+	sections = append(sections, m.help.View(helpKeyMap))
+
+	baseLayer := lipgloss.NewLayer(lipgloss.JoinVertical(lipgloss.Left, sections...))
 
 	layers := []*lipgloss.Layer{baseLayer}
 	if m.options.IsOpen() {
@@ -240,5 +453,8 @@ func (m model) View() tea.View {
 		layers = append(layers, optionsLayer)
 	}
 
-	return tea.NewView(lipgloss.NewCanvas(layers...).Render())
+	v := tea.NewView(lipgloss.NewCanvas(layers...).Render())
+	v.MouseMode = tea.MouseModeCellMotion
+
+	return v
 }
@@ -7,6 +7,7 @@ var (
 	MutedColor   = lipgloss.Color("240")
 	LightColor   = lipgloss.Color("15")
 	ErrorColor   = lipgloss.Color("9")
+	WarningColor = lipgloss.Color("214")
 
 	InputContainerStyle = lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
@@ -16,4 +17,14 @@ var (
 					BorderForeground(PrimaryColor)
 	ErrorInputContainerStyle = InputContainerStyle.
 					BorderForeground(ErrorColor)
+
+	ErrorTextStyle = lipgloss.NewStyle().
+			Foreground(ErrorColor).
+			PaddingLeft(2)
+	WarningTextStyle = lipgloss.NewStyle().
+				Foreground(WarningColor).
+				PaddingLeft(2)
+	HintTextStyle = lipgloss.NewStyle().
+			Foreground(MutedColor).
+			PaddingLeft(2)
 )
@@ -6,15 +6,22 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/vitor-mariano/regex-tui/internal/screen"
 	"github.com/vitor-mariano/regex-tui/internal/tty"
+	"github.com/vitor-mariano/regex-tui/pkg/regex"
+	_ "github.com/vitor-mariano/regex-tui/pkg/regex/posix"
+	_ "github.com/vitor-mariano/regex-tui/pkg/regex/re2"
+	_ "github.com/vitor-mariano/regex-tui/pkg/regex/regexp2"
 )
 
 const (
-	defaultRegex = "[A-Z]\\w+"
-	defaultText  = "Hello World!"
+	defaultRegex   = "[A-Z]\\w+"
+	defaultText    = "Hello World!"
+	defaultTimeout = 250 * time.Millisecond
 )
 
 func main() {
@@ -53,17 +60,51 @@ func getInitialConfig() screen.Config {
 	empty := flag.Bool("empty", false, "Start with empty expression and text")
 	flag.BoolVar(empty, "e", false, "Start with empty expression and text (shorthand)")
 
-	regex := flag.String("regex", "", "Initial regex pattern")
-	flag.StringVar(regex, "r", "", "Initial regex pattern (shorthand)")
+	pattern := flag.String("regex", "", "Initial regex pattern")
+	flag.StringVar(pattern, "r", "", "Initial regex pattern (shorthand)")
 
 	text := flag.String("text", "", "Initial text subject")
 	flag.StringVar(text, "t", "", "Initial text subject (shorthand)")
 
+	replace := flag.String("replace", "", "Start in replace mode with the given template")
+
 	noGlobal := flag.Bool("no-global", false, "Disable global flag (match only first occurrence)")
 
+	literal := flag.Bool("literal", false, "Match the expression as plain text, like grep -F")
+	flag.BoolVar(literal, "F", false, "Match the expression as plain text (shorthand)")
+
+	showWhitespace := flag.Bool("show-whitespace", false, "Render spaces, tabs, line breaks and invisible characters as glyphs")
+
+	lineNumbers := flag.Bool("line-numbers", false, "Show line numbers and the number of matches on each line")
+	flag.BoolVar(lineNumbers, "n", false, "Show line numbers (shorthand)")
+
 	insensitive := flag.Bool("insensitive", false, "Enable case-insensitive flag")
 
-	regexp2 := flag.Bool("regexp2", false, "Use regexp2 engine (partial PCRE compatibility)")
+	multiline := flag.Bool("multiline", false, "Enable multiline flag (^ and $ match at line boundaries)")
+
+	dotAll := flag.Bool("dotall", false, "Enable dot-all flag (. matches \\n)")
+
+	ungreedy := flag.Bool("ungreedy", false, "Enable ungreedy flag (swap meaning of x* and x*?)")
+
+	engineName := flag.String("engine", regex.DefaultEngine, "Regex engine ("+engineNames()+")")
+
+	regexp2 := flag.Bool("regexp2", false, "Use regexp2 engine (alias for --engine regexp2)")
+
+	posix := flag.Bool("posix", false, "Use POSIX engine (alias for --engine posix)")
+
+	compare := flag.String("compare", "", "Compare matches with another engine ("+engineNames()+")")
+
+	timeout := flag.Duration("timeout", defaultTimeout, "Abort regexp2 matches running longer than this (0 disables)")
+
+	rightToLeft := flag.Bool("right-to-left", false, "Match from right to left (regexp2 only)")
+
+	ecmaScript := flag.Bool("ecmascript", false, "Use ECMAScript-compliant behavior (regexp2 only)")
+
+	explicitCapture := flag.Bool("explicit-capture", false, "Only capture named or numbered groups (regexp2 only)")
+
+	ignoreWhitespace := flag.Bool("ignore-whitespace", false, "Ignore unescaped whitespace and allow # comments (regexp2 only)")
+
+	re2Compat := flag.Bool("re2-compat", false, "Use RE2 compatibility mode (regexp2 only)")
 
 	flag.Parse()
 
@@ -71,9 +112,33 @@ func getInitialConfig() screen.Config {
 		log.Fatal("error: cannot use --text/-t flag when reading from stdin")
 	}
 
+	if *regexp2 && *posix {
+		log.Fatal("error: cannot use --regexp2 and --posix flags together")
+	}
+
+	if *regexp2 {
+		*engineName = "regexp2"
+	} else if *posix {
+		*engineName = "posix"
+	}
+
+	engine, ok := regex.LookupEngine(*engineName)
+	if !ok {
+		log.Fatalf("error: unknown engine %q, available engines are %s\n", *engineName, engineNames())
+	}
+
+	var compareEngine string
+	if *compare != "" {
+		engine, ok := regex.LookupEngine(*compare)
+		if !ok {
+			log.Fatalf("error: unknown engine %q, available engines are %s\n", *compare, engineNames())
+		}
+		compareEngine = engine.Name
+	}
+
 	var regexExpression string
-	if *regex != "" {
-		regexExpression = *regex
+	if *pattern != "" {
+		regexExpression = *pattern
 	} else if !*empty {
 		regexExpression = defaultRegex
 	}
@@ -93,11 +158,41 @@ func getInitialConfig() screen.Config {
 
 	global := !*noGlobal
 
+	var flags regex.Flags
+	flags = flags.Set(regex.Insensitive, *insensitive)
+	flags = flags.Set(regex.Multiline, *multiline)
+	flags = flags.Set(regex.DotAll, *dotAll)
+	flags = flags.Set(regex.Ungreedy, *ungreedy)
+	flags = flags.Set(regex.Literal, *literal)
+	flags = flags.Set(regex.RightToLeft, *rightToLeft)
+	flags = flags.Set(regex.ECMAScript, *ecmaScript)
+	flags = flags.Set(regex.ExplicitCapture, *explicitCapture)
+	flags = flags.Set(regex.IgnorePatternWhitespace, *ignoreWhitespace)
+	flags = flags.Set(regex.RE2Compat, *re2Compat)
+
+	if flags&^(regex.CommonFlags|engine.Flags) != 0 {
+		log.Fatalf("error: the given options are not supported by the %s engine\n", engine.Name)
+	}
+
 	return screen.Config{
-		InitialExpression: regexExpression,
-		InitialSubject:    textSubject,
-		Global:            global,
-		Insensitive:       *insensitive,
-		Regexp2:           *regexp2,
+		InitialExpression:  regexExpression,
+		InitialSubject:     textSubject,
+		InitialReplacement: *replace,
+		Global:             global,
+		ShowWhitespace:     *showWhitespace,
+		ShowLineNumbers:    *lineNumbers,
+		Flags:              flags,
+		Engine:             engine.Name,
+		CompareEngine:      compareEngine,
+		Timeout:            *timeout,
 	}
 }
+
+func engineNames() string {
+	var names []string
+	for _, engine := range regex.Engines() {
+		names = append(names, strings.ToLower(engine.Name))
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -103,6 +103,7 @@ func (m *Model) View() string {
 
 func (m *Model) SetItems(items []string) {
 	m.items = items
+	m.current = min(m.current, max(len(items)-1, 0))
 }
 
 func (m *Model) SetSelected(items ...string) {
@@ -115,6 +116,21 @@ func (m *Model) SetSelected(items ...string) {
 	}
 }
 
+// Deselect removes items from the selection, notifying the ones that were
+// selected.
+func (m *Model) Deselect(items ...string) {
+	for _, item := range items {
+		if !m.selected.Contains(item) {
+			continue
+		}
+
+		m.selected.Remove(item)
+		if m.onToggle != nil {
+			m.onToggle(item, false)
+		}
+	}
+}
+
 func (m *Model) OnToggle(onToggle func(item string, selected bool)) {
 	m.onToggle = onToggle
 }
@@ -0,0 +1,63 @@
+package regexview
+
+import (
+	"time"
+
+	. "github.com/vitor-mariano/regex-tui/pkg/regex"
+)
+
+// cacheKey identifies the inputs that matching results depend on.
+type cacheKey struct {
+	expression string
+	engine     string
+	flags      Flags
+	timeout    time.Duration
+	global     bool
+	value      string
+}
+
+// cache holds the results of matching the current value, so that a frame
+// runs the expression once however many times they are read. Backtracking
+// engines can take up to the timeout for each run.
+type cache struct {
+	key      cacheKey
+	valid    bool
+	matches  []Match
+	err      error
+	captures map[int][][]Span
+	// replacement is the result of the last Replace.
+	replacement *replacement
+}
+
+type replacement struct {
+	template string
+	result   string
+	err      error
+}
+
+// results returns the cache for the current inputs, clearing it when any of
+// them changed.
+func (m *Model) results() *cache {
+	key := cacheKey{
+		expression: m.compiled,
+		engine:     m.engine,
+		flags:      m.flags,
+		timeout:    m.timeout,
+		global:     m.global,
+		value:      m.value,
+	}
+
+	if m.cache.key != key {
+		m.cache = cache{key: key}
+	}
+
+	return &m.cache
+}
+
+// KeepResults reuses the results of prev, a model replaced by m, as long as
+// they were found with the same inputs.
+func (m *Model) KeepResults(prev *Model) {
+	if prev != nil {
+		m.cache = prev.cache
+	}
+}
@@ -0,0 +1,89 @@
+package regexview
+
+import (
+	"slices"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	. "github.com/vitor-mariano/regex-tui/pkg/regex"
+)
+
+// groupColors are cycled through to highlight capture groups, by index.
+var groupColors = []string{"114", "209", "141", "80", "174", "186"}
+
+func groupStyle(index int) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Background(lipgloss.Color(groupColors[(index-1)%len(groupColors)])).
+		Foreground(lipgloss.Color("232")).
+		Bold(true)
+}
+
+// innermostGroup returns the innermost group of match covering the bytes
+// [start, end), and the number of groups covering them.
+func innermostGroup(match Match, start, end int) (*Group, int) {
+	var (
+		inner *Group
+		depth int
+	)
+
+	for i := range match.Groups {
+		g := &match.Groups[i]
+		if !g.Matched || g.Empty() || g.Start > start || g.End < end {
+			continue
+		}
+
+		depth++
+		// Nested groups come after the groups containing them.
+		if inner == nil || g.Len() <= inner.Len() {
+			inner = g
+		}
+	}
+
+	return inner, depth
+}
+
+// writeMatch draws the bytes [start, end) of match, highlighting the
+// innermost capture group covering each part with its color and underlining
+// the parts covered by nested groups. The rest is drawn with style s.
+func (m *Model) writeMatch(b *strings.Builder, match Match, start, end int, s *lipgloss.Style) {
+	bounds := []int{start, end}
+	for _, g := range match.Groups {
+		for _, bound := range []int{g.Start, g.End} {
+			if bound > start && bound < end {
+				bounds = append(bounds, bound)
+			}
+		}
+	}
+	slices.Sort(bounds)
+	bounds = slices.Compact(bounds)
+
+	for i := 1; i < len(bounds); i++ {
+		from, to := bounds[i-1], bounds[i]
+
+		g, depth := innermostGroup(match, from, to)
+		if g == nil {
+			m.write(b, from, to, s)
+			continue
+		}
+
+		gs := groupStyle(g.Index).Underline(depth > 1)
+		m.write(b, from, to, &gs)
+	}
+}
+
+// Legend maps the colors of the capture groups of the current expression to
+// their indexes and names, or returns "" when it has none.
+func (m *Model) Legend() string {
+	names := m.SubexpNames()
+	if len(names) < 2 {
+		return ""
+	}
+
+	chips := make([]string, 0, len(names)-1)
+	for i, name := range names[1:] {
+		label := Group{Index: i + 1, Name: name}.Label()
+		chips = append(chips, groupStyle(i+1).Render(" "+label+" "))
+	}
+
+	return strings.Join(chips, " ")
+}
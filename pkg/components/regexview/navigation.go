@@ -0,0 +1,92 @@
+package regexview
+
+import (
+	"slices"
+
+	. "github.com/vitor-mariano/regex-tui/pkg/regex"
+)
+
+// focusedIndex returns the index of the focused match in matches, following
+// it when its position changed. Focus is dropped when the focused span is no
+// longer matched, e.g. after editing the value or changing flags.
+func (m *Model) focusedIndex(matches []Match) int {
+	if m.focused < 0 || m.focused < len(matches) && matches[m.focused].Span == m.focusedSpan {
+		return m.focused
+	}
+
+	m.focused = slices.IndexFunc(matches, func(match Match) bool {
+		return match.Span == m.focusedSpan
+	})
+
+	return m.focused
+}
+
+// FocusedMatch returns the match stepped to with NextMatch or PreviousMatch.
+func (m *Model) FocusedMatch() (Match, bool) {
+	matches, err := m.Matches()
+	if err != nil {
+		return Match{}, false
+	}
+
+	focused := m.focusedIndex(matches)
+	if focused < 0 {
+		return Match{}, false
+	}
+
+	return matches[focused], true
+}
+
+// Captures returns every span captured by each group of match, when the
+// engine records the captures of repeated groups.
+func (m *Model) Captures(match Match) ([][]Span, bool, error) {
+	capturer, ok := m.expression.(Capturer)
+	if !ok {
+		return nil, false, nil
+	}
+
+	c := m.results()
+	if captures, ok := c.captures[match.Ordinal]; ok {
+		return captures, true, nil
+	}
+
+	captures, err := capturer.Captures(m.value, match.Ordinal)
+	if err != nil {
+		return nil, true, err
+	}
+
+	if c.captures == nil {
+		c.captures = make(map[int][][]Span)
+	}
+	c.captures[match.Ordinal] = captures
+
+	return captures, true, nil
+}
+
+// NextMatch focuses the match after the focused one, wrapping around to the
+// first, and scrolls the view to show it.
+func (m *Model) NextMatch() {
+	m.stepMatch(1)
+}
+
+// PreviousMatch focuses the match before the focused one, wrapping around to
+// the last, and scrolls the view to show it.
+func (m *Model) PreviousMatch() {
+	m.stepMatch(-1)
+}
+
+func (m *Model) stepMatch(n int) {
+	matches, err := m.Matches()
+	if err != nil || len(matches) == 0 {
+		m.focused = -1
+		return
+	}
+
+	focused := m.focusedIndex(matches)
+	if focused < 0 && n < 0 {
+		focused = 0
+	}
+
+	m.focused = (focused + n + len(matches)) % len(matches)
+	m.focusedSpan = matches[m.focused].Span
+	m.scrollTo(matches[m.focused].Start)
+}
@@ -1,13 +1,16 @@
 package regexview
 
 import (
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"charm.land/lipgloss/v2"
-	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/truncate"
+	"github.com/vitor-mariano/regex-tui/internal/styles"
 	. "github.com/vitor-mariano/regex-tui/pkg/regex"
-	"github.com/vitor-mariano/regex-tui/pkg/regex/re2"
-	"github.com/vitor-mariano/regex-tui/pkg/regex/regexp2"
+	"github.com/vitor-mariano/regex-tui/pkg/regex/explain"
 )
 
 var (
@@ -19,89 +22,281 @@ var (
 			Background(lipgloss.Color("117")).
 			Foreground(lipgloss.Color("232")).
 			Bold(true)
+	evenEmptyMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("220")).
+				Bold(true)
+	oddEmptyMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("117")).
+				Bold(true)
+	differentMatchStyle = lipgloss.NewStyle().
+				Background(styles.ErrorColor).
+				Foreground(lipgloss.Color("232")).
+				Bold(true)
+	differentEmptyMatchStyle = lipgloss.NewStyle().
+					Foreground(styles.ErrorColor).
+					Bold(true)
+	focusedMatchStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("213")).
+				Foreground(lipgloss.Color("232")).
+				Bold(true).
+				Underline(true)
+	focusedEmptyMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("213")).
+				Bold(true)
+	whitespaceStyle = lipgloss.NewStyle().
+			Foreground(styles.MutedColor)
+	cursorStyle = lipgloss.NewStyle().
+			Foreground(styles.PrimaryColor).
+			Reverse(true)
+	gutterStyle = lipgloss.NewStyle().
+			Foreground(styles.MutedColor)
+	gutterCountStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("220"))
+	bannerStyle = lipgloss.NewStyle().
+			Foreground(styles.ErrorColor).
+			Bold(true)
 )
 
+// emptyMatchMarker is inserted at the position of zero-width matches, which
+// would otherwise not be visible.
+const emptyMatchMarker = "│"
+
 type Model struct {
-	expression    Regex
-	baseExpStr    string
-	global        bool
-	insensitive   bool
-	regexp2       bool
-	value         string
+	expression Regex
+	baseExpStr string
+	// compiled is the source of expression, which may differ from baseExpStr
+	// after a failed recompilation.
+	compiled string
+	cache    cache
+	global   bool
+	// showWhitespace renders whitespace and invisible characters as glyphs.
+	showWhitespace bool
+	// showLineNumbers draws a gutter with the line numbers of the value and
+	// the number of matches on each line.
+	showLineNumbers bool
+	flags           Flags
+	timeout         time.Duration
+	engine          string
+	value           string
+	// reference is compared against to highlight the matches it does not
+	// share.
+	reference *Model
+	// offset is the first row shown.
+	offset int
+	// focused is the index of the match stepped to, or -1 when none is, and
+	// focusedSpan its span.
+	focused     int
+	focusedSpan Span
+	// cursor is the byte offset of the cursor in the value, or -1 when it is
+	// hidden.
+	cursor        int
 	width, height int
 }
 
 func New(width, height int) *Model {
 	return &Model{
-		width:  width,
-		height: height,
+		engine:  DefaultEngine,
+		focused: -1,
+		cursor:  -1,
+		width:   width,
+		height:  height,
 	}
 }
 
-func (m *Model) renderContainer(s string) string {
+func (m *Model) View() string {
+	var (
+		matches []Match
+		rows    []string
+	)
+
+	if m.expression != nil {
+		var err error
+		matches, err = m.Matches()
+
+		var timeoutErr *TimeoutError
+		if errors.As(err, &timeoutErr) {
+			message := truncate.StringWithTail(timeoutErr.Error(), uint(max(m.width, 1)), "…")
+			rows = append(rows, bannerStyle.Render(message))
+		}
+	}
+
+	m.focusedIndex(matches)
+
+	lines := m.layout()
+	height := m.height - len(rows)
+	offset := min(m.offset, max(len(lines)-height, 0))
+	different := m.different(matches)
+
+	var counts map[int]int
+	if m.showLineNumbers {
+		counts = make(map[int]int)
+		for _, match := range matches {
+			counts[match.Line]++
+		}
+	}
+
+	next := 0
+	for _, l := range lines[offset:min(offset+height, len(lines))] {
+		row := m.renderLine(l, matches, &next, different)
+		if m.showLineNumbers {
+			row = m.gutter(l, counts) + row
+		}
+		rows = append(rows, row)
+	}
+
 	return lipgloss.Place(
 		m.width, m.height,
-		lipgloss.Left, lipgloss.Left,
-		wordwrap.String(s, m.width),
+		lipgloss.Left, lipgloss.Top,
+		strings.Join(rows, "\n"),
 	)
 }
 
-func (m *Model) View() string {
+// Matches returns the matches in the current value. Only the first match is
+// returned when global is disabled.
+func (m *Model) Matches() ([]Match, error) {
 	if m.expression == nil {
-		return m.renderContainer(m.value)
+		return nil, nil
+	}
+
+	c := m.results()
+	if !c.valid {
+		n := -1
+		if !m.global {
+			n = 1
+		}
+
+		c.matches, c.err = FindMatches(m.expression, m.value, n)
+		c.valid = true
 	}
 
-	var b strings.Builder
-	lastIndex := 0
+	return c.matches, c.err
+}
 
-	var matches [][]int
-	if m.global {
-		matches = m.expression.FindAllStringIndex(m.value, -1)
-	} else {
-		matches = [][]int{m.expression.FindStringIndex(m.value)}
+// Status summarizes the matches in the current value, counting zero-width
+// matches separately. The position of the focused match is given instead of
+// the total when there is one.
+func (m *Model) Status() string {
+	if m.expression == nil {
+		return ""
 	}
-	for i, match := range matches {
-		s := &evenMatchStyle
-		if i%2 == 1 {
-			s = &oddMatchStyle
+
+	matches, err := m.Matches()
+	if err != nil {
+		return err.Error()
+	}
+
+	empty := 0
+	for _, match := range matches {
+		if match.Empty() {
+			empty++
 		}
+	}
 
-		b.WriteString(m.value[lastIndex:match[0]])
-		b.WriteString(s.Render(m.value[match[0]:match[1]]))
-		lastIndex = match[1]
+	var status string
+	switch focused := m.focusedIndex(matches); {
+	case len(matches) == 0:
+		return "no matches"
+	case focused >= 0:
+		status = fmt.Sprintf("match %d of %d", focused+1, len(matches))
+	case len(matches) == 1:
+		status = "1 match"
+	default:
+		status = fmt.Sprintf("%d matches", len(matches))
 	}
 
-	b.WriteString(m.value[lastIndex:])
+	if empty > 0 {
+		status += fmt.Sprintf(", %d zero-width", empty)
+	}
 
-	return m.renderContainer(b.String())
+	return status
 }
 
-func (m *Model) newRegexp(expression string) (Regex, error) {
-	if m.regexp2 {
-		return regexp2.New(expression)
+// different returns the spans of matches that the reference does not share.
+func (m *Model) different(matches []Match) map[Span]bool {
+	if m.reference == nil {
+		return nil
+	}
+
+	refMatches, err := m.reference.Matches()
+	if err != nil {
+		return nil
+	}
+
+	different := make(map[Span]bool, len(matches))
+	for _, match := range matches {
+		different[match.Span] = true
+	}
+	for _, match := range refMatches {
+		delete(different, match.Span)
 	}
 
-	return re2.New(expression)
+	return different
 }
 
-func (m *Model) setRegexp(expression string) error {
-	prefix := ""
-	if m.insensitive {
-		prefix = "(?i)"
+// Differences returns the number of matches that the reference does not
+// share.
+func (m *Model) Differences() int {
+	matches, err := m.Matches()
+	if err != nil {
+		return 0
 	}
 
-	regex, err := m.newRegexp(prefix + expression)
+	return len(m.different(matches))
+}
+
+// SetReference highlights the matches not shared with ref, or disables the
+// comparison when ref is nil.
+func (m *Model) SetReference(ref *Model) {
+	m.reference = ref
+}
+
+// WithEngine returns a copy of m without an expression, using the given
+// engine. The copy has no reference.
+func (m *Model) WithEngine(engine string) *Model {
+	c := *m
+	c.engine = engine
+	c.expression = nil
+	c.baseExpStr = ""
+	c.reference = nil
+	c.compiled = ""
+	c.cache = cache{}
+	c.focused = -1
+	c.cursor = -1
+
+	return &c
+}
+
+func (m *Model) newRegexp(expression string) (Regex, error) {
+	return Compile(m.engine, expression, Options{Flags: m.flags, Timeout: m.timeout})
+}
+
+func (m *Model) setRegexp(expression string) error {
+	regex, err := m.newRegexp(expression)
 	if err != nil {
 		return err
 	}
 
 	m.expression = regex
+	m.compiled = expression
 	return nil
 }
 
+// recompile applies changed settings to the last expression that compiled,
+// if any. Until one does, there is nothing to match with.
+func (m *Model) recompile() error {
+	if m.expression == nil {
+		return nil
+	}
+
+	return m.setRegexp(m.baseExpStr)
+}
+
 func (m *Model) SetExpression(expression string) error {
 	err := m.setRegexp(expression)
 	if err == nil {
+		if expression != m.baseExpStr {
+			m.focused = -1
+		}
 		m.baseExpStr = expression
 	}
 
@@ -112,20 +307,80 @@ func (m *Model) SetGlobal(global bool) {
 	m.global = global
 }
 
-func (m *Model) SetInsensitive(insensitive bool) {
-	m.insensitive = insensitive
-	m.setRegexp(m.baseExpStr)
+func (m *Model) SetShowWhitespace(show bool) {
+	m.showWhitespace = show
 }
 
-func (m *Model) SetRegexp2(regexp2 bool) error {
-	m.regexp2 = regexp2
-	return m.setRegexp(m.baseExpStr)
+func (m *Model) SetShowLineNumbers(show bool) {
+	m.showLineNumbers = show
+}
+
+func (m *Model) SetFlag(flag Flags, enabled bool) error {
+	m.flags = m.flags.Set(flag, enabled)
+	return m.recompile()
+}
+
+// SetTimeout bounds the duration of each match for backtracking engines.
+func (m *Model) SetTimeout(timeout time.Duration) error {
+	m.timeout = timeout
+	return m.recompile()
+}
+
+// SetEngine selects the registered engine with the given name.
+func (m *Model) SetEngine(engine string) error {
+	m.engine = engine
+	return m.recompile()
+}
+
+func (m *Model) Engine() string {
+	return m.engine
+}
+
+// QuotedExpression returns the regex equivalent of expression when the
+// Literal flag is enabled.
+func (m *Model) QuotedExpression(expression string) (string, bool) {
+	engine, ok := LookupEngine(m.engine)
+	if !ok || !m.flags.Has(Literal) {
+		return "", false
+	}
+
+	return engine.QuoteExpression(expression), true
+}
+
+// Explain describes expression as parsed by the selected engine.
+func (m *Model) Explain(expression string) ([]*explain.Node, error) {
+	return Explain(m.engine, expression, m.flags)
+}
+
+// Warnings returns the parts of expression that may backtrack excessively,
+// when the selected engine backtracks.
+func (m *Model) Warnings(expression string) []Warning {
+	engine, ok := LookupEngine(m.engine)
+	if !ok || !engine.Backtracking || m.flags.Has(Literal) {
+		return nil
+	}
+
+	return Lint(expression)
+}
+
+// UnsupportedFeatures returns the features used by expression that the
+// selected engine does not support.
+func (m *Model) UnsupportedFeatures(expression string) Features {
+	if m.flags.Has(Literal) {
+		return 0
+	}
+
+	return UnsupportedFeatures(m.engine, expression)
 }
 
 func (m *Model) SetValue(value string) {
 	m.value = value
 }
 
+func (m *Model) Value() string {
+	return m.value
+}
+
 func (m *Model) SetWidth(width int) {
 	m.width = width
 }
@@ -143,3 +398,37 @@ func (m *Model) Validate(expression string) error {
 	_, err := m.newRegexp(expression)
 	return err
 }
+
+// SubexpNames returns the capture group names of the current expression,
+// indexed by group slot.
+func (m *Model) SubexpNames() []string {
+	if m.expression == nil {
+		return nil
+	}
+
+	return m.expression.SubexpNames()
+}
+
+// Replace returns the current value with matches substituted by the
+// expansion of repl. Only the first match is replaced when global is
+// disabled.
+func (m *Model) Replace(repl string) (string, error) {
+	if m.expression == nil {
+		return m.value, nil
+	}
+
+	c := m.results()
+	if r := c.replacement; r != nil && r.template == repl {
+		return r.result, r.err
+	}
+
+	n := -1
+	if !m.global {
+		n = 1
+	}
+
+	result, err := m.expression.ReplaceAllString(m.value, repl, n)
+	c.replacement = &replacement{repl, result, err}
+
+	return result, err
+}
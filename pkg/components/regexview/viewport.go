@@ -0,0 +1,345 @@
+package regexview
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"charm.land/lipgloss/v2"
+	. "github.com/vitor-mariano/regex-tui/pkg/regex"
+)
+
+// line is a row of the view, showing the bytes [start, end) of the value.
+// last is set on the row ending a line of the value, where zero-width
+// matches at its end are drawn. number is the 1-based line of the value the
+// row belongs to.
+type line struct {
+	start, end int
+	last       bool
+	number     int
+}
+
+// countWidth is the width of the per-line match counts in the gutter.
+const countWidth = 3
+
+// whitespaceGlyphs replace whitespace and invisible characters when they are
+// shown.
+var whitespaceGlyphs = map[rune]string{
+	' ':      "·",
+	'\t':     "→",
+	'\r':     "␍",
+	'\n':     "↵",
+	'\u00a0': "⍽",
+	'\u200b': "¤",
+	'\u200c': "¤",
+	'\u200d': "¤",
+	'\u2060': "¤",
+	'\ufeff': "¤",
+}
+
+// cellWidth returns the number of cells taken by r.
+func (m *Model) cellWidth(r rune) int {
+	if _, ok := whitespaceGlyphs[r]; ok && m.showWhitespace {
+		return 1
+	}
+
+	return lipgloss.Width(string(r))
+}
+
+// render styles text with s, if not nil, showing whitespace as glyphs when
+// enabled. Glyphs outside matches are muted.
+func (m *Model) render(text string, s *lipgloss.Style) string {
+	if m.showWhitespace {
+		var b strings.Builder
+		for _, r := range text {
+			glyph, ok := whitespaceGlyphs[r]
+			switch {
+			case !ok:
+				b.WriteRune(r)
+			case s == nil:
+				b.WriteString(whitespaceStyle.Render(glyph))
+			default:
+				b.WriteString(glyph)
+			}
+		}
+		text = b.String()
+	}
+
+	if s == nil {
+		return text
+	}
+
+	return s.Render(text)
+}
+
+// layout wraps the value to the width of the view, breaking rows after
+// spaces when possible.
+func (m *Model) layout() []line {
+	markers := m.emptyMatchMarkers()
+
+	var lines []line
+	for start, number := 0, 1; ; number++ {
+		end := strings.IndexByte(m.value[start:], '\n')
+		if end < 0 {
+			return append(lines, m.wrap(start, len(m.value), number, markers)...)
+		}
+
+		lines = append(lines, m.wrap(start, start+end, number, markers)...)
+		start += end + 1
+	}
+}
+
+// emptyMatchMarkers counts the markers drawn at each byte offset of the
+// value, one per zero-width match.
+func (m *Model) emptyMatchMarkers() map[int]int {
+	matches, _ := m.Matches()
+
+	markers := make(map[int]int)
+	for _, match := range matches {
+		if match.Empty() {
+			markers[match.Start]++
+		}
+	}
+
+	return markers
+}
+
+// wrap splits the bytes [start, end) of the value into rows, counting a cell
+// for each marker drawn between the characters. The cells drawn after the
+// last character must fit on the last row.
+func (m *Model) wrap(start, end, number int, markers map[int]int) []line {
+	width := max(m.width-m.gutterWidth(), 1)
+
+	var lines []line
+	for {
+		rowEnd, rowWidth, lastSpace := start, 0, -1
+		for rowEnd < end {
+			r, size := utf8.DecodeRuneInString(m.value[rowEnd:end])
+			w := markers[rowEnd] + m.cellWidth(r)
+			if rowWidth+w > width {
+				break
+			}
+
+			rowWidth += w
+			rowEnd += size
+			if r == ' ' {
+				lastSpace = rowEnd
+			}
+		}
+
+		if rowEnd >= end && (rowWidth+m.tailWidth(end, markers) <= width || rowEnd == start) {
+			return append(lines, line{start, end, true, number})
+		}
+		if lastSpace > start {
+			rowEnd = lastSpace
+		} else if rowEnd == start {
+			_, size := utf8.DecodeRuneInString(m.value[rowEnd:end])
+			rowEnd += size
+		}
+
+		lines = append(lines, line{start, rowEnd, false, number})
+		start = rowEnd
+	}
+}
+
+// tailWidth returns the number of cells drawn after the line of the value
+// ending at end: its zero-width match markers and, when whitespace is shown,
+// the line break glyph. While the cursor is shown, a column is reserved for
+// it at the end of every line, as in textarea, unless it can be drawn over
+// the line break glyph.
+func (m *Model) tailWidth(end int, markers map[int]int) int {
+	width := markers[end]
+	if m.showWhitespace && end < len(m.value) || m.cursor >= 0 {
+		width++
+	}
+
+	return width
+}
+
+// gutterWidth returns the width of the line number gutter, or 0 when it is
+// hidden.
+func (m *Model) gutterWidth() int {
+	if !m.showLineNumbers {
+		return 0
+	}
+
+	return m.numberWidth() + countWidth + 4
+}
+
+func (m *Model) numberWidth() int {
+	return len(strconv.Itoa(strings.Count(m.value, "\n") + 1))
+}
+
+// gutter draws the line number and match count of the line l starts, or a
+// blank gutter on the rows continuing a wrapped line.
+func (m *Model) gutter(l line, counts map[int]int) string {
+	first := l.start == 0 || m.value[l.start-1] == '\n'
+	if !first {
+		return gutterStyle.Render(strings.Repeat(" ", m.gutterWidth()-2) + "│ ")
+	}
+
+	count := ""
+	if n := counts[l.number]; n > 99 {
+		count = "99+"
+	} else if n > 0 {
+		count = strconv.Itoa(n)
+	}
+
+	return gutterStyle.Render(fmt.Sprintf("%*d ", m.numberWidth(), l.number)) +
+		gutterCountStyle.Render(fmt.Sprintf("%*s", countWidth, count)) +
+		gutterStyle.Render(" │ ")
+}
+
+// drawnOn reports whether match is drawn on l, at least partially.
+func (l line) drawnOn(match Match) bool {
+	if match.Empty() {
+		return match.Start >= l.start && (match.Start < l.end || l.last && match.Start == l.end)
+	}
+
+	return match.Start < l.end && match.End > l.start
+}
+
+// before reports whether match is entirely drawn before l.
+func (l line) before(match Match) bool {
+	if match.Empty() {
+		return match.Start < l.start
+	}
+
+	return match.End <= l.start
+}
+
+// renderLine draws l, highlighting the matches starting from matches[*next]
+// and advancing next past the ones drawn before it.
+func (m *Model) renderLine(l line, matches []Match, next *int, different map[Span]bool) string {
+	for *next < len(matches) && l.before(matches[*next]) {
+		*next++
+	}
+
+	// The line break ending the row is drawn along with it when whitespace
+	// is shown, leaving zero-width matches after it to the next row.
+	if m.showWhitespace && l.last && l.end < len(m.value) {
+		l.end++
+		l.last = false
+	}
+
+	var b strings.Builder
+	pos := l.start
+	for i := *next; i < len(matches) && l.drawnOn(matches[i]); i++ {
+		match := matches[i]
+		start, end := max(match.Start, l.start), min(match.End, l.end)
+		m.write(&b, pos, start, nil)
+		pos = end
+
+		if match.Empty() {
+			s := &evenEmptyMatchStyle
+			if i == m.focused {
+				s = &focusedEmptyMatchStyle
+			} else if different[match.Span] {
+				s = &differentEmptyMatchStyle
+			} else if i%2 == 1 {
+				s = &oddEmptyMatchStyle
+			}
+
+			b.WriteString(s.Render(emptyMatchMarker))
+			continue
+		}
+
+		switch {
+		case i == m.focused:
+			m.write(&b, start, end, &focusedMatchStyle)
+		case different[match.Span]:
+			m.write(&b, start, end, &differentMatchStyle)
+		case i%2 == 1:
+			m.writeMatch(&b, match, start, end, &oddMatchStyle)
+		default:
+			m.writeMatch(&b, match, start, end, &evenMatchStyle)
+		}
+	}
+	m.write(&b, pos, l.end, nil)
+
+	if l.last && m.cursor == l.end {
+		b.WriteString(cursorStyle.Render(" "))
+	}
+
+	return b.String()
+}
+
+// write draws the bytes [start, end) of the value with style s, drawing the
+// cursor over them if it is within.
+func (m *Model) write(b *strings.Builder, start, end int, s *lipgloss.Style) {
+	if m.cursor < start || m.cursor >= end {
+		b.WriteString(m.render(m.value[start:end], s))
+		return
+	}
+
+	_, size := utf8.DecodeRuneInString(m.value[m.cursor:end])
+	b.WriteString(m.render(m.value[start:m.cursor], s))
+	b.WriteString(m.render(m.value[m.cursor:m.cursor+size], &cursorStyle))
+	b.WriteString(m.render(m.value[m.cursor+size:end], s))
+}
+
+// SetCursor shows the cursor at the given byte offset of the value, or hides
+// it if offset is -1. The view scrolls to keep a moved cursor visible.
+func (m *Model) SetCursor(offset int) {
+	if offset == m.cursor {
+		return
+	}
+
+	m.cursor = offset
+	if offset >= 0 {
+		m.scrollTo(offset)
+	}
+}
+
+// scrollTo scrolls the view, if needed, to show the row with the given byte
+// offset of the value.
+func (m *Model) scrollTo(offset int) {
+	for row, l := range m.layout() {
+		if offset >= l.start && (offset < l.end || l.last && offset == l.end) {
+			if row < m.offset {
+				m.offset = row
+			} else if row >= m.offset+m.height {
+				m.offset = row - m.height + 1
+			}
+			return
+		}
+	}
+}
+
+func (m *Model) maxOffset() int {
+	return max(len(m.layout())-m.height, 0)
+}
+
+// ScrollBy scrolls the view down by n rows, or up if n is negative.
+func (m *Model) ScrollBy(n int) {
+	m.offset = min(max(m.offset+n, 0), m.maxOffset())
+}
+
+func (m *Model) PageUp() {
+	m.ScrollBy(-m.height)
+}
+
+func (m *Model) PageDown() {
+	m.ScrollBy(m.height)
+}
+
+func (m *Model) ScrollToTop() {
+	m.offset = 0
+}
+
+func (m *Model) ScrollToBottom() {
+	m.offset = m.maxOffset()
+}
+
+// ScrollStatus describes the visible rows, or returns "" when the value fits
+// in the view.
+func (m *Model) ScrollStatus() string {
+	rows := len(m.layout())
+	if rows <= m.height {
+		return ""
+	}
+
+	offset := min(m.offset, rows-m.height)
+	return fmt.Sprintf("rows %d-%d of %d", offset+1, offset+m.height, rows)
+}
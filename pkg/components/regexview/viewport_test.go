@@ -0,0 +1,54 @@
+package regexview
+
+import (
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	_ "github.com/vitor-mariano/regex-tui/pkg/regex/re2"
+)
+
+func TestViewFitsWidth(t *testing.T) {
+	tests := []struct {
+		name          string
+		expr          string
+		value         string
+		width, height int
+		whitespace    bool
+		cursor        bool
+	}{
+		{"zero-width matches everywhere", `x*`, "abcdefghijklmnopqrstuvwxyz", 10, 10, false, false},
+		{"word boundaries", `\b`, strings.Repeat("lorem ipsum dolor sit amet\n", 20), 30, 16, false, false},
+		{"zero-width match at the end of a full row", `$`, "abcde", 5, 5, false, false},
+		{"wide characters", `\b`, "日本語のテキスト", 7, 5, false, false},
+		{"line break glyph after a full row", `f`, "abcde\nf", 5, 5, true, false},
+		{"line break glyph after a marker", `$`, "abcd\nabcd", 5, 5, true, false},
+		{"cursor after a full row", `a`, "abcde", 5, 5, false, true},
+		{"cursor after a full row and a marker", `$`, "abcd\nabcde", 5, 5, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(tt.width, tt.height)
+			m.SetGlobal(true)
+			m.SetShowWhitespace(tt.whitespace)
+			m.SetValue(tt.value)
+			if err := m.SetExpression(tt.expr); err != nil {
+				t.Fatal(err)
+			}
+			if tt.cursor {
+				m.SetCursor(len(tt.value))
+			}
+
+			rows := strings.Split(m.View(), "\n")
+			if len(rows) != tt.height {
+				t.Errorf("got %d rows, want %d", len(rows), tt.height)
+			}
+			for i, row := range rows {
+				if w := lipgloss.Width(row); w > tt.width {
+					t.Errorf("row %d is %d cells wide, want at most %d: %q", i, w, tt.width, row)
+				}
+			}
+		})
+	}
+}
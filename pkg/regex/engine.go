@@ -0,0 +1,143 @@
+package regex
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/vitor-mariano/regex-tui/pkg/regex/explain"
+)
+
+// DefaultEngine is the name of the engine used when none is selected.
+const DefaultEngine = "RE2"
+
+// Engine describes a regex engine that can be selected by name.
+type Engine struct {
+	Name        string
+	Description string
+	// Flags lists the flags supported by the engine. Flags outside
+	// CommonFlags are only offered while the engine is selected.
+	Flags Flags
+	// Features lists the syntax constructs supported by the engine.
+	Features Features
+	// Backtracking engines can take exponential time to match, so their
+	// expressions are checked with Lint.
+	Backtracking bool
+	New          func(expr string, opts Options) (Regex, error)
+	// Explain describes expr in plain words. It is optional.
+	Explain func(expr string, flags Flags) ([]*explain.Node, error)
+	// Quote escapes the metacharacters of a literal text. It defaults to
+	// regexp.QuoteMeta.
+	Quote func(s string) string
+}
+
+// QuoteExpression returns expr escaped so the engine matches it literally.
+func (e Engine) QuoteExpression(expr string) string {
+	if e.Quote == nil {
+		return regexp.QuoteMeta(expr)
+	}
+
+	return e.Quote(expr)
+}
+
+var engines []Engine
+
+// Register makes an engine available by name. Engines register themselves
+// from init, so importing their package is enough to make them selectable.
+// It panics if an engine with the same name is already registered.
+func Register(engine Engine) {
+	if _, ok := LookupEngine(engine.Name); ok {
+		panic("regex: Register called twice for engine " + engine.Name)
+	}
+
+	engines = append(engines, engine)
+}
+
+// LookupEngine returns the engine registered with the given name, ignoring
+// case.
+func LookupEngine(name string) (Engine, bool) {
+	for _, engine := range engines {
+		if strings.EqualFold(engine.Name, name) {
+			return engine, true
+		}
+	}
+
+	return Engine{}, false
+}
+
+// Engines returns the registered engines, starting with the default one and
+// followed by the others in alphabetical order.
+func Engines() []Engine {
+	sorted := slices.Clone(engines)
+	slices.SortFunc(sorted, func(a, b Engine) int {
+		switch {
+		case a.Name == DefaultEngine:
+			return -1
+		case b.Name == DefaultEngine:
+			return 1
+		}
+
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	return sorted
+}
+
+// UnsupportedFeatures returns the features used by expr that the named engine
+// does not support.
+func UnsupportedFeatures(name, expr string) Features {
+	engine, ok := LookupEngine(name)
+	if !ok {
+		return 0
+	}
+
+	return DetectFeatures(expr) &^ engine.Features
+}
+
+// EnginesSupporting returns the registered engines supporting all of the
+// given features, in the order of Engines.
+func EnginesSupporting(features Features) []Engine {
+	var supporting []Engine
+	for _, engine := range Engines() {
+		if engine.Features.Has(features) {
+			supporting = append(supporting, engine)
+		}
+	}
+
+	return supporting
+}
+
+// Explain describes expr as parsed by the named engine, applying flags as
+// Compile does.
+func Explain(name, expr string, flags Flags) ([]*explain.Node, error) {
+	engine, ok := LookupEngine(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown engine %q", name)
+	}
+	if engine.Explain == nil {
+		return nil, fmt.Errorf("the %s engine cannot explain expressions", engine.Name)
+	}
+
+	if flags.Has(Literal) {
+		expr = engine.QuoteExpression(expr)
+	}
+
+	return engine.Explain(expr, flags&(CommonFlags|engine.Flags)&^Literal)
+}
+
+// Compile compiles expr with the named engine, dropping the flags specific to
+// other engines. With the Literal flag, expr is quoted first.
+func Compile(name, expr string, opts Options) (Regex, error) {
+	engine, ok := LookupEngine(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown engine %q", name)
+	}
+
+	if opts.Flags.Has(Literal) {
+		expr = engine.QuoteExpression(expr)
+	}
+
+	opts.Flags &= (CommonFlags | engine.Flags) &^ Literal
+	return engine.New(expr, opts)
+}
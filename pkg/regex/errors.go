@@ -0,0 +1,76 @@
+package regex
+
+import (
+	"fmt"
+	"time"
+)
+
+// SyntaxError describes an expression that failed to compile.
+type SyntaxError struct {
+	Message string
+	// Offset is the byte offset in the expression where the error was
+	// detected, or -1 if the engine does not report it.
+	Offset int
+	Err    error
+}
+
+func (e *SyntaxError) Error() string {
+	return e.Message
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError is returned when a match is aborted after exceeding
+// Options.Timeout. Engines may only notice the timeout some time after it
+// expires, so Elapsed is the time actually spent matching.
+type TimeoutError struct {
+	Timeout time.Duration
+	Elapsed time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("match aborted after %v, exceeding the %v timeout", e.Elapsed.Round(time.Millisecond), e.Timeout)
+}
+
+// Unbalanced scans expr and returns the offset of the first ")" without an
+// opening parenthesis and of the last "[" or "(" left open, or -1 for each if
+// there is none. Escapes and character classes are skipped, so it can locate
+// syntax errors engines only report for the expression as a whole.
+func Unbalanced(expr string) (unexpected, unclosed int) {
+	unexpected, unclosed = -1, -1
+	var parens []int
+	class := -1
+
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case c == '\\':
+			i++
+		case class >= 0:
+			if c == ']' && i > class+1 && !(i == class+2 && expr[class+1] == '^') {
+				class = -1
+			}
+		case c == '[':
+			class = i
+		case c == '(':
+			parens = append(parens, i)
+		case c == ')':
+			if len(parens) == 0 {
+				if unexpected < 0 {
+					unexpected = i
+				}
+				continue
+			}
+			parens = parens[:len(parens)-1]
+		}
+	}
+
+	if class >= 0 {
+		unclosed = class
+	} else if len(parens) > 0 {
+		unclosed = parens[len(parens)-1]
+	}
+
+	return unexpected, unclosed
+}
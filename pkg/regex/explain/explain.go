@@ -0,0 +1,107 @@
+// Package explain describes regular expressions in plain words, as a tree of
+// the constructs they are made of.
+package explain
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Node describes a construct of an expression along with the constructs it
+// contains.
+type Node struct {
+	Description string
+	Children    []*Node
+}
+
+func leaf(format string, args ...any) *Node {
+	return &Node{Description: fmt.Sprintf(format, args...)}
+}
+
+func literal(s string, foldCase bool) *Node {
+	if foldCase {
+		return leaf("literal %s (case-insensitive)", strconv.Quote(s))
+	}
+
+	return leaf("literal %s", strconv.Quote(s))
+}
+
+func capture(index int, name string, children []*Node) *Node {
+	if name != "" {
+		return &Node{fmt.Sprintf("capture group %d %q", index, name), children}
+	}
+
+	return &Node{fmt.Sprintf("capture group %d", index), children}
+}
+
+func alternation(alternatives []*Node) *Node {
+	return &Node{"one of", alternatives}
+}
+
+// sequence groups children as a single alternative.
+func sequence(children []*Node) *Node {
+	switch len(children) {
+	case 0:
+		return leaf("empty string")
+	case 1:
+		return children[0]
+	}
+
+	return &Node{"sequence", children}
+}
+
+// repetition describes how many times sub is repeated. max is -1 when
+// unbounded.
+func repetition(sub *Node, min, max int, mode string) *Node {
+	var times string
+	switch {
+	case min == 0 && max == -1:
+		times = "zero or more times"
+	case min == 1 && max == -1:
+		times = "one or more times"
+	case min == 0 && max == 1:
+		times = "optional"
+	case max == -1:
+		times = fmt.Sprintf("at least %d times", min)
+	case min == max:
+		times = fmt.Sprintf("exactly %d times", min)
+	default:
+		times = fmt.Sprintf("%d to %d times", min, max)
+	}
+	if mode != "" {
+		times += " (" + mode + ")"
+	}
+
+	// Quantified leaves read better on a single line.
+	if len(sub.Children) == 0 {
+		return leaf("%s, %s", sub.Description, times)
+	}
+
+	return &Node{times, []*Node{sub}}
+}
+
+// classNames names the classes of shorthand escapes, as printed by
+// regexp/syntax and as written in expressions.
+var classNames = map[string]string{
+	`[0-9]`:             "digit",
+	`[^0-9]`:            "non-digit",
+	`[0-9A-Z_a-z]`:      "word character",
+	`[^0-9A-Z_a-z]`:     "non-word character",
+	`[\t\n\f\r ]`:       "whitespace",
+	`[^\t\n\f\r ]`:      "non-whitespace",
+	`\d`:                "digit",
+	`\D`:                "non-digit",
+	`\w`:                "word character",
+	`\W`:                "non-word character",
+	`\s`:                "whitespace",
+	`\S`:                "non-whitespace",
+	`[\x00-\x{10FFFF}]`: "any character",
+}
+
+func class(s string) *Node {
+	if name, ok := classNames[s]; ok {
+		return leaf("%s", name)
+	}
+
+	return leaf("character class %s", s)
+}
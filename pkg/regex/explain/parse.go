@@ -0,0 +1,598 @@
+package explain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options holds the modes an expression is parsed with. Inline groups such as
+// (?i) change them for the rest of the enclosing group.
+type Options struct {
+	Insensitive             bool
+	Multiline               bool
+	DotAll                  bool
+	ExplicitCapture         bool
+	IgnorePatternWhitespace bool
+}
+
+var (
+	errMissingParen  = errors.New("missing closing )")
+	errMissingRepeat = errors.New("quantifier without anything to repeat")
+
+	countedQuantifier = regexp.MustCompile(`^\{(\d+)(,(\d*))?\}`)
+)
+
+type namedCapture struct {
+	node     *Node
+	name     string
+	children []*Node
+}
+
+type parser struct {
+	expr string
+	pos  int
+	opts Options
+	// groups counts the unnamed captures. Named ones are numbered after
+	// them, once the whole expression is parsed.
+	groups int
+	named  []namedCapture
+}
+
+// Parse describes an expression in the syntax of regexp2, which follows the
+// one of .NET.
+func Parse(expr string, opts Options) ([]*Node, error) {
+	p := &parser{expr: expr, opts: opts}
+
+	nodes, err := p.alternation()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.expr) {
+		return nil, fmt.Errorf("unexpected ) at offset %d", p.pos)
+	}
+
+	numbers := map[string]int{}
+	for _, nc := range p.named {
+		n, err := strconv.Atoi(nc.name)
+		if err != nil {
+			var ok bool
+			if n, ok = numbers[nc.name]; !ok {
+				n = p.groups + len(numbers) + 1
+				numbers[nc.name] = n
+			}
+		} else {
+			nc.name = ""
+		}
+
+		*nc.node = *capture(n, nc.name, nc.children)
+	}
+
+	return nodes, nil
+}
+
+func (p *parser) more() bool {
+	return p.pos < len(p.expr)
+}
+
+func (p *parser) consume(prefix string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+
+	return false
+}
+
+// alternation parses up to the end of the enclosing group.
+func (p *parser) alternation() ([]*Node, error) {
+	alternatives, err := p.alternatives()
+	if err != nil {
+		return nil, err
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+
+	nodes := make([]*Node, 0, len(alternatives))
+	for _, alternative := range alternatives {
+		nodes = append(nodes, sequence(alternative))
+	}
+
+	return []*Node{alternation(nodes)}, nil
+}
+
+func (p *parser) alternatives() ([][]*Node, error) {
+	var alternatives [][]*Node
+	for {
+		nodes, err := p.concatenation()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, nodes)
+
+		if !p.consume("|") {
+			return alternatives, nil
+		}
+	}
+}
+
+func (p *parser) concatenation() ([]*Node, error) {
+	var (
+		nodes    []*Node
+		lit      strings.Builder
+		litFold  bool
+		flushLit = func() {
+			if lit.Len() > 0 {
+				nodes = append(nodes, literal(lit.String(), litFold))
+				lit.Reset()
+			}
+		}
+	)
+
+	for p.more() {
+		if p.skipWhitespace() {
+			continue
+		}
+		if c := p.expr[p.pos]; c == '|' || c == ')' {
+			break
+		}
+
+		fold := p.opts.Insensitive
+		node, r, err := p.atom()
+		if err != nil {
+			return nil, err
+		}
+		if node == nil && r < 0 {
+			continue
+		}
+
+		p.skipWhitespace()
+		min, max, mode, quantified := p.quantifier()
+
+		if node == nil && !quantified {
+			if lit.Len() > 0 && fold != litFold {
+				flushLit()
+			}
+			lit.WriteRune(r)
+			litFold = fold
+			continue
+		}
+
+		flushLit()
+		if node == nil {
+			node = literal(string(r), fold)
+		}
+		if quantified {
+			node = repetition(node, min, max, mode)
+		}
+		nodes = append(nodes, node)
+	}
+	flushLit()
+
+	return nodes, nil
+}
+
+// skipWhitespace skips whitespace and comments in IgnorePatternWhitespace
+// mode, reporting whether it did.
+func (p *parser) skipWhitespace() bool {
+	if !p.opts.IgnorePatternWhitespace {
+		return false
+	}
+
+	start := p.pos
+	for p.more() {
+		switch c := p.expr[p.pos]; {
+		case c == '#':
+			if i := strings.IndexByte(p.expr[p.pos:], '\n'); i >= 0 {
+				p.pos += i + 1
+			} else {
+				p.pos = len(p.expr)
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			p.pos++
+		default:
+			return p.pos > start
+		}
+	}
+
+	return p.pos > start
+}
+
+func (p *parser) quantifier() (min, max int, mode string, ok bool) {
+	if !p.more() {
+		return 0, 0, "", false
+	}
+
+	switch p.expr[p.pos] {
+	case '*':
+		min, max = 0, -1
+		p.pos++
+	case '+':
+		min, max = 1, -1
+		p.pos++
+	case '?':
+		min, max = 0, 1
+		p.pos++
+	case '{':
+		m := countedQuantifier.FindStringSubmatch(p.expr[p.pos:])
+		if m == nil {
+			return 0, 0, "", false
+		}
+		p.pos += len(m[0])
+		min, _ = strconv.Atoi(m[1])
+		max = min
+		if m[2] != "" {
+			max = -1
+			if m[3] != "" {
+				max, _ = strconv.Atoi(m[3])
+			}
+		}
+	default:
+		return 0, 0, "", false
+	}
+
+	if p.consume("?") {
+		mode = "lazy"
+	} else if p.consume("+") {
+		mode = "possessive"
+	}
+
+	return min, max, mode, true
+}
+
+// atom parses a single construct. Literal characters are returned as r with a
+// nil node so they can be merged, and constructs that match nothing, such as
+// comments and inline options, return neither.
+func (p *parser) atom() (node *Node, r rune, err error) {
+	switch c := p.expr[p.pos]; c {
+	case '(':
+		p.pos++
+		node, err := p.group()
+		return node, -1, err
+	case '[':
+		return p.class(), -1, nil
+	case '\\':
+		return p.escape()
+	case '.':
+		p.pos++
+		if p.opts.DotAll {
+			return leaf("any character"), -1, nil
+		}
+		return leaf("any character except newline"), -1, nil
+	case '^':
+		p.pos++
+		if p.opts.Multiline {
+			return leaf("start of line"), -1, nil
+		}
+		return leaf("start of text"), -1, nil
+	case '$':
+		p.pos++
+		if p.opts.Multiline {
+			return leaf("end of line"), -1, nil
+		}
+		return leaf("end of text"), -1, nil
+	case '*', '+', '?':
+		return nil, -1, errMissingRepeat
+	case '{':
+		if countedQuantifier.MatchString(p.expr[p.pos:]) {
+			return nil, -1, errMissingRepeat
+		}
+	}
+
+	r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+	p.pos += size
+
+	return nil, r, nil
+}
+
+// group parses the construct following an opening parenthesis.
+func (p *parser) group() (*Node, error) {
+	saved := p.opts
+	defer func() { p.opts = saved }()
+
+	var (
+		description string
+		named       string
+	)
+
+	switch {
+	case p.consume("?#"):
+		i := strings.IndexByte(p.expr[p.pos:], ')')
+		if i < 0 {
+			return nil, errMissingParen
+		}
+		p.pos += i + 1
+		return nil, nil
+	case p.consume("?:"):
+	case p.consume("?="):
+		description = "lookahead"
+	case p.consume("?!"):
+		description = "negative lookahead"
+	case p.consume("?<="):
+		description = "lookbehind"
+	case p.consume("?<!"):
+		description = "negative lookbehind"
+	case p.consume("?>"):
+		description = "atomic group"
+	case strings.HasPrefix(p.expr[p.pos:], "?("):
+		p.pos++
+		return p.conditional()
+	case p.consume("?<"), p.consume("?P<"):
+		named = p.until('>')
+	case p.consume("?'"):
+		named = p.until('\'')
+	case p.consume("?"):
+		scoped, err := p.inlineOptions()
+		if err != nil {
+			return nil, err
+		}
+		if !scoped {
+			// The options apply to the rest of the enclosing group.
+			saved = p.opts
+			return nil, nil
+		}
+	case !p.opts.ExplicitCapture:
+		p.groups++
+		description = fmt.Sprintf("capture group %d", p.groups)
+	}
+
+	children, err := p.alternation()
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume(")") {
+		return nil, errMissingParen
+	}
+
+	switch {
+	case named != "" && strings.Contains(named, "-"):
+		return &Node{fmt.Sprintf("balancing group %q", named), children}, nil
+	case named != "":
+		node := &Node{}
+		p.named = append(p.named, namedCapture{node, named, children})
+		return node, nil
+	case description == "":
+		return sequence(children), nil
+	}
+
+	return &Node{description, children}, nil
+}
+
+// until returns the text up to delim, consuming both.
+func (p *parser) until(delim byte) string {
+	i := strings.IndexByte(p.expr[p.pos:], delim)
+	if i < 0 {
+		s := p.expr[p.pos:]
+		p.pos = len(p.expr)
+		return s
+	}
+
+	s := p.expr[p.pos : p.pos+i]
+	p.pos += i + 1
+	return s
+}
+
+// inlineOptions parses options such as "i-s)" or "i-s:", reporting whether
+// they are scoped to a group.
+func (p *parser) inlineOptions() (bool, error) {
+	enabled := true
+	for p.more() {
+		c := p.expr[p.pos]
+		p.pos++
+
+		switch c {
+		case '-':
+			enabled = false
+		case 'i':
+			p.opts.Insensitive = enabled
+		case 'm':
+			p.opts.Multiline = enabled
+		case 's':
+			p.opts.DotAll = enabled
+		case 'n':
+			p.opts.ExplicitCapture = enabled
+		case 'x':
+			p.opts.IgnorePatternWhitespace = enabled
+		case ')':
+			return false, nil
+		case ':':
+			return true, nil
+		default:
+			return false, fmt.Errorf("unrecognized grouping construct (?%c", c)
+		}
+	}
+
+	return false, errMissingParen
+}
+
+// conditional parses (?(condition)yes|no), starting at the condition.
+func (p *parser) conditional() (*Node, error) {
+	start := p.pos
+	depth := 0
+	for ; p.more(); p.pos++ {
+		switch p.expr[p.pos] {
+		case '\\':
+			p.pos++
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if !p.more() {
+		return nil, errMissingParen
+	}
+	p.pos++
+	condition := p.expr[start+1 : p.pos-1]
+
+	alternatives, err := p.alternatives()
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume(")") {
+		return nil, errMissingParen
+	}
+
+	description := fmt.Sprintf("if %s matches", condition)
+	if _, err := strconv.Atoi(condition); err == nil || isName(condition) {
+		description = fmt.Sprintf("if group %s matched", condition)
+	}
+
+	children := []*Node{{"then", alternatives[0]}}
+	if len(alternatives) > 1 {
+		children = append(children, &Node{"else", alternatives[1]})
+	}
+
+	return &Node{description, children}, nil
+}
+
+func isName(s string) bool {
+	for _, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+// class parses a bracketed character class, including .NET subtractions
+// such as [a-z-[aeiou]].
+func (p *parser) class() *Node {
+	start := p.pos
+	p.pos++
+	p.consume("^")
+	p.consume("]")
+
+	depth := 1
+	for p.more() && depth > 0 {
+		switch p.expr[p.pos] {
+		case '\\':
+			p.pos++
+		case '[':
+			if p.pos > start && p.expr[p.pos-1] == '-' {
+				depth++
+			}
+		case ']':
+			depth--
+		}
+		p.pos++
+	}
+	// A trailing backslash skips past the end.
+	p.pos = min(p.pos, len(p.expr))
+
+	return class(p.expr[start:p.pos])
+}
+
+func (p *parser) escape() (*Node, rune, error) {
+	p.pos++
+	if !p.more() {
+		return nil, -1, errors.New("illegal \\ at end of pattern")
+	}
+
+	c := p.expr[p.pos]
+	p.pos++
+
+	switch c {
+	case 'd', 'D', 'w', 'W', 's', 'S':
+		return class(`\` + string(c)), -1, nil
+	case 'b':
+		return leaf("word boundary"), -1, nil
+	case 'B':
+		return leaf("not a word boundary"), -1, nil
+	case 'A':
+		return leaf("start of text"), -1, nil
+	case 'z':
+		return leaf("end of text"), -1, nil
+	case 'Z':
+		return leaf("end of text or before a final newline"), -1, nil
+	case 'G':
+		return leaf("end of the previous match"), -1, nil
+	case 'p', 'P':
+		name := string(p.expr[p.pos-1])
+		if p.consume("{") {
+			name = p.until('}')
+		}
+		if c == 'P' {
+			return leaf("not in Unicode class %s", name), -1, nil
+		}
+		return leaf("Unicode class %s", name), -1, nil
+	case 'k':
+		if p.more() {
+			switch p.expr[p.pos] {
+			case '<':
+				p.pos++
+				return leaf("backreference to group %q", p.until('>')), -1, nil
+			case '\'':
+				p.pos++
+				return leaf("backreference to group %q", p.until('\'')), -1, nil
+			}
+		}
+	case 'n':
+		return nil, '\n', nil
+	case 't':
+		return nil, '\t', nil
+	case 'r':
+		return nil, '\r', nil
+	case 'f':
+		return nil, '\f', nil
+	case 'v':
+		return nil, '\v', nil
+	case 'a':
+		return nil, '\a', nil
+	case 'e':
+		return nil, '\x1b', nil
+	case 'x':
+		return p.codePoint(2)
+	case 'u':
+		return p.codePoint(4)
+	case 'c':
+		if p.more() {
+			p.pos++
+			return nil, rune(unicode.ToUpper(rune(p.expr[p.pos-1])) ^ 0x40), nil
+		}
+	case '0':
+		end := p.pos
+		for end < len(p.expr) && end < p.pos+2 && p.expr[end] >= '0' && p.expr[end] <= '7' {
+			end++
+		}
+		n, _ := strconv.ParseInt("0"+p.expr[p.pos:end], 8, 32)
+		p.pos = end
+		return nil, rune(n), nil
+	}
+
+	if c >= '1' && c <= '9' {
+		start := p.pos - 1
+		for p.more() && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+			p.pos++
+		}
+		return leaf("backreference to group %s", p.expr[start:p.pos]), -1, nil
+	}
+
+	r, size := utf8.DecodeRuneInString(p.expr[p.pos-1:])
+	p.pos += size - 1
+
+	return nil, r, nil
+}
+
+func (p *parser) codePoint(digits int) (*Node, rune, error) {
+	if p.pos+digits > len(p.expr) {
+		return nil, -1, errors.New("insufficient hex digits")
+	}
+
+	n, err := strconv.ParseUint(p.expr[p.pos:p.pos+digits], 16, 32)
+	if err != nil {
+		return nil, -1, errors.New("invalid hex digits")
+	}
+	p.pos += digits
+
+	return nil, rune(n), nil
+}
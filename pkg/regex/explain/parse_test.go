@@ -0,0 +1,81 @@
+package explain
+
+import (
+	"strings"
+	"testing"
+)
+
+// outline renders nodes one per line, indenting children.
+func outline(b *strings.Builder, nodes []*Node, indent string) {
+	for _, node := range nodes {
+		b.WriteString(indent + node.Description + "\n")
+		outline(b, node.Children, indent+"  ")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr string
+		opts Options
+		want string
+	}{
+		{`a+?`, Options{}, "literal \"a\", one or more times (lazy)\n"},
+		{`x{2,3}`, Options{}, "literal \"x\", 2 to 3 times\n"},
+		{`(?i)ab`, Options{}, "literal \"ab\" (case-insensitive)\n"},
+		{`ab`, Options{Insensitive: true}, "literal \"ab\" (case-insensitive)\n"},
+		{`a(?#comment)b`, Options{}, "literal \"ab\"\n"},
+		{`[a-z-[aeiou]]`, Options{}, "character class [a-z-[aeiou]]\n"},
+		{`[a\`, Options{}, "character class [a\\\n"},
+		{`[a`, Options{}, "character class [a\n"},
+		{
+			`(?<year>\d{4})-(\d\d)`, Options{},
+			"capture group 2 \"year\"\n  digit, exactly 4 times\nliteral \"-\"\ncapture group 1\n  digit\n  digit\n",
+		},
+		{`\k<year>`, Options{}, "backreference to group \"year\"\n"},
+		{
+			`(?=x)y|z`, Options{},
+			"one of\n  sequence\n    lookahead\n      literal \"x\"\n    literal \"y\"\n  literal \"z\"\n",
+		},
+		{`(?>a+)b`, Options{}, "atomic group\n  literal \"a\", one or more times\nliteral \"b\"\n"},
+		{
+			`(?(1)a|b)`, Options{},
+			"if group 1 matched\n  then\n    literal \"a\"\n  else\n    literal \"b\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			nodes, err := Parse(tt.expr, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b strings.Builder
+			outline(&b, nodes, "")
+			if got := b.String(); got != tt.want {
+				t.Errorf("Parse(%q) =\n%s\nwant\n%s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`(`, "missing closing )"},
+		{`a)`, "unexpected ) at offset 1"},
+		{`*a`, "quantifier without anything to repeat"},
+		{`a\`, "illegal \\ at end of pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr, Options{})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Parse(%q) error = %v, want %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+}
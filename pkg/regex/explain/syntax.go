@@ -0,0 +1,82 @@
+package explain
+
+import "regexp/syntax"
+
+// maxClassLength bounds the length of the character classes printed in
+// descriptions, as Unicode classes expand to many ranges.
+const maxClassLength = 40
+
+// Syntax describes an expression parsed by regexp/syntax.
+func Syntax(re *syntax.Regexp) []*Node {
+	if re.Op == syntax.OpConcat {
+		return syntaxNodes(re.Sub)
+	}
+
+	return []*Node{syntaxNode(re)}
+}
+
+func syntaxNodes(subs []*syntax.Regexp) []*Node {
+	nodes := make([]*Node, 0, len(subs))
+	for _, sub := range subs {
+		nodes = append(nodes, syntaxNode(sub))
+	}
+
+	return nodes
+}
+
+func syntaxNode(re *syntax.Regexp) *Node {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return leaf("nothing (never matches)")
+	case syntax.OpEmptyMatch:
+		return leaf("empty string")
+	case syntax.OpLiteral:
+		return literal(string(re.Rune), re.Flags&syntax.FoldCase != 0)
+	case syntax.OpCharClass:
+		s := re.String()
+		if len(s) > maxClassLength {
+			return leaf("character class with %d ranges", len(re.Rune)/2)
+		}
+		return class(s)
+	case syntax.OpAnyCharNotNL:
+		return leaf("any character except newline")
+	case syntax.OpAnyChar:
+		return leaf("any character")
+	case syntax.OpBeginLine:
+		return leaf("start of line")
+	case syntax.OpEndLine:
+		return leaf("end of line")
+	case syntax.OpBeginText:
+		return leaf("start of text")
+	case syntax.OpEndText:
+		return leaf("end of text")
+	case syntax.OpWordBoundary:
+		return leaf("word boundary")
+	case syntax.OpNoWordBoundary:
+		return leaf("not a word boundary")
+	case syntax.OpCapture:
+		return capture(re.Cap, re.Name, Syntax(re.Sub[0]))
+	case syntax.OpStar:
+		return repetition(syntaxNode(re.Sub[0]), 0, -1, syntaxMode(re))
+	case syntax.OpPlus:
+		return repetition(syntaxNode(re.Sub[0]), 1, -1, syntaxMode(re))
+	case syntax.OpQuest:
+		return repetition(syntaxNode(re.Sub[0]), 0, 1, syntaxMode(re))
+	case syntax.OpRepeat:
+		return repetition(syntaxNode(re.Sub[0]), re.Min, re.Max, syntaxMode(re))
+	case syntax.OpConcat:
+		return sequence(syntaxNodes(re.Sub))
+	case syntax.OpAlternate:
+		return alternation(syntaxNodes(re.Sub))
+	}
+
+	return leaf("%s", re.String())
+}
+
+func syntaxMode(re *syntax.Regexp) string {
+	if re.Flags&syntax.NonGreedy != 0 {
+		return "lazy"
+	}
+
+	return ""
+}
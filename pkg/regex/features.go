@@ -0,0 +1,117 @@
+package regex
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Features is a set of syntax constructs that not every engine supports.
+type Features uint
+
+const (
+	Lookahead Features = 1 << iota
+	Lookbehind
+	Backreferences
+	AtomicGroups
+	PossessiveQuantifiers
+	Conditionals
+	UnicodeClasses
+)
+
+var featureNames = []struct {
+	feature Features
+	name    string
+}{
+	{Lookahead, "lookahead"},
+	{Lookbehind, "lookbehind"},
+	{Backreferences, "backreferences"},
+	{AtomicGroups, "atomic groups"},
+	{PossessiveQuantifiers, "possessive quantifiers"},
+	{Conditionals, "conditionals"},
+	{UnicodeClasses, `\p{} classes`},
+}
+
+func (f Features) Has(feature Features) bool {
+	return f&feature == feature
+}
+
+// Names returns the human readable names of the features in f.
+func (f Features) Names() []string {
+	var names []string
+	for _, fn := range featureNames {
+		if f.Has(fn.feature) {
+			names = append(names, fn.name)
+		}
+	}
+
+	return names
+}
+
+func (f Features) String() string {
+	return strings.Join(f.Names(), ", ")
+}
+
+var countedQuantifier = regexp.MustCompile(`^\{\d+(,\d*)?\}`)
+
+// DetectFeatures returns the features used by expr. It only scans the syntax,
+// so it may report features of an expression that does not compile.
+func DetectFeatures(expr string) Features {
+	var features Features
+	class := -1
+	// quantified is true right after a quantifier, where a '+' makes it
+	// possessive.
+	quantified := false
+
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		wasQuantified := quantified
+		quantified = false
+
+		if c == '\\' && i+1 < len(expr) {
+			i++
+			switch e := expr[i]; {
+			case e == 'p' || e == 'P':
+				features |= UnicodeClasses
+			case class >= 0:
+			case e >= '1' && e <= '9', e == 'k' && i+1 < len(expr) && strings.ContainsRune("<'{", rune(expr[i+1])):
+				features |= Backreferences
+			}
+			continue
+		}
+
+		if class >= 0 {
+			if c == ']' && i > class+1 && !(i == class+2 && expr[class+1] == '^') {
+				class = -1
+			}
+			continue
+		}
+
+		rest := expr[i:]
+		switch {
+		case c == '[':
+			class = i
+		case strings.HasPrefix(rest, "(?=") || strings.HasPrefix(rest, "(?!"):
+			features |= Lookahead
+		case strings.HasPrefix(rest, "(?<=") || strings.HasPrefix(rest, "(?<!"):
+			features |= Lookbehind
+		case strings.HasPrefix(rest, "(?>"):
+			features |= AtomicGroups
+		case strings.HasPrefix(rest, "(?("):
+			features |= Conditionals
+		case c == '(' && strings.HasPrefix(rest, "(?"):
+			// Skip the '?' so it is not taken for a quantifier.
+			i++
+		case c == '+' && wasQuantified:
+			features |= PossessiveQuantifiers
+		case c == '*' || c == '+' || c == '?':
+			quantified = true
+		case c == '{':
+			if loc := countedQuantifier.FindStringIndex(rest); loc != nil {
+				i += loc[1] - 1
+				quantified = true
+			}
+		}
+	}
+
+	return features
+}
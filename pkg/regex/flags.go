@@ -0,0 +1,37 @@
+package regex
+
+// Flags is a set of matching modes applied when compiling an expression.
+type Flags uint
+
+const (
+	Insensitive Flags = 1 << iota
+	Multiline
+	DotAll
+	Ungreedy
+	// Literal matches the expression as plain text. It is applied by Compile
+	// by quoting the expression for the engine.
+	Literal
+
+	// The following flags map to regexp2 compile options.
+	RightToLeft
+	ECMAScript
+	ExplicitCapture
+	IgnorePatternWhitespace
+	RE2Compat
+)
+
+// CommonFlags holds the flags offered for every engine.
+const CommonFlags = Insensitive | Multiline | DotAll | Literal
+
+func (f Flags) Has(flag Flags) bool {
+	return f&flag == flag
+}
+
+// Set returns a copy of f with flag enabled or disabled.
+func (f Flags) Set(flag Flags, enabled bool) Flags {
+	if enabled {
+		return f | flag
+	}
+
+	return f &^ flag
+}
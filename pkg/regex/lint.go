@@ -0,0 +1,230 @@
+package regex
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Warning reports a part of an expression that may backtrack excessively.
+// Start and End are byte offsets of the part in the expression.
+type Warning struct {
+	Start, End int
+	Message    string
+}
+
+// lintNode is an item of a sequence: an atom or a group, with the quantifier
+// applied to it.
+type lintNode struct {
+	start, end int
+	// text is the source of an atom, used to tell which characters it
+	// matches. It is empty for groups.
+	text         string
+	alternatives [][]*lintNode
+	atomic       bool
+	min, max     int
+	possessive   bool
+}
+
+func (n *lintNode) unbounded() bool {
+	return n.max < 0 && !n.possessive
+}
+
+// containsUnbounded reports whether an item of alternatives, at any depth, is
+// repeated without bound.
+func containsUnbounded(alternatives [][]*lintNode) bool {
+	for _, sequence := range alternatives {
+		for _, n := range sequence {
+			if n.unbounded() || !n.atomic && containsUnbounded(n.alternatives) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Lint walks expr looking for shapes known to cause catastrophic backtracking
+// in backtracking engines: nested unbounded quantifiers, repeated
+// alternatives matching the same characters and adjacent quantifiers
+// competing for the same characters.
+func Lint(expr string) []Warning {
+	tokens := Tokenize(expr)
+	var warnings []Warning
+	lintItems(expr, lintSequence(expr, tokens, 0, len(tokens)), &warnings)
+
+	return warnings
+}
+
+// lintSequence builds the items of tokens[from:to], split by alternations.
+func lintSequence(expr string, tokens []Token, from, to int) [][]*lintNode {
+	var (
+		alternatives [][]*lintNode
+		sequence     []*lintNode
+	)
+
+	for i := from; i < to; i++ {
+		token := tokens[i]
+		var n *lintNode
+
+		switch {
+		case token.Kind == TokenAlternation:
+			alternatives = append(alternatives, sequence)
+			sequence = nil
+			continue
+		case token.Kind == TokenGroup && token.Pair > i:
+			prefix := expr[token.Start:token.End]
+			n = &lintNode{alternatives: lintSequence(expr, tokens, i+1, token.Pair), atomic: prefix == "(?>"}
+			if strings.HasPrefix(prefix, "(?=") || strings.HasPrefix(prefix, "(?!") ||
+				strings.HasPrefix(prefix, "(?<=") || strings.HasPrefix(prefix, "(?<!") {
+				// Lookarounds match no characters.
+				n.alternatives = nil
+			}
+			i = token.Pair
+		case token.Kind == TokenClass && token.Pair > i:
+			n = &lintNode{}
+			i = token.Pair
+		case token.Kind == TokenGroup, token.Kind == TokenComment, token.Kind == TokenQuantifier:
+			continue
+		default:
+			n = &lintNode{}
+		}
+
+		n.start, n.end = token.Start, tokens[i].End
+		if n.alternatives == nil && token.Kind != TokenAnchor && token.Kind != TokenGroup {
+			n.text = expr[n.start:n.end]
+		}
+		n.min, n.max = 1, 1
+
+		if i+1 < to && tokens[i+1].Kind == TokenQuantifier {
+			i++
+			quantifier := expr[tokens[i].Start:tokens[i].End]
+			n.min, n.max = parseQuantifier(quantifier)
+			n.possessive = len(quantifier) > 1 && strings.HasSuffix(quantifier, "+")
+			n.end = tokens[i].End
+		}
+
+		sequence = append(sequence, n)
+	}
+
+	return append(alternatives, sequence)
+}
+
+func parseQuantifier(q string) (min, max int) {
+	switch q[0] {
+	case '*':
+		return 0, -1
+	case '+':
+		return 1, -1
+	case '?':
+		return 0, 1
+	}
+
+	m := quantifierBounds.FindStringSubmatch(q)
+	min, _ = strconv.Atoi(m[1])
+	max = min
+	if m[2] != "" {
+		max = -1
+		if m[3] != "" {
+			max, _ = strconv.Atoi(m[3])
+		}
+	}
+
+	return min, max
+}
+
+func lintItems(expr string, alternatives [][]*lintNode, warnings *[]Warning) {
+	for _, sequence := range alternatives {
+		for i, n := range sequence {
+			if n.atomic {
+				continue
+			}
+
+			fragment := expr[n.start:n.end]
+			switch {
+			case n.unbounded() && containsUnbounded(n.alternatives):
+				*warnings = append(*warnings, Warning{n.start, n.end,
+					fmt.Sprintf("nested quantifiers in %s can backtrack exponentially", fragment)})
+			case n.unbounded() && len(n.alternatives) > 1:
+				if a, b, ok := overlappingAlternatives(expr, n.alternatives); ok {
+					*warnings = append(*warnings, Warning{n.start, n.end,
+						fmt.Sprintf("alternatives %s and %s of %s match the same characters and can backtrack exponentially", a, b, fragment)})
+				}
+			}
+
+			if i > 0 {
+				prev := sequence[i-1]
+				if prev.unbounded() && n.unbounded() && overlap(prev.text, n.text) {
+					*warnings = append(*warnings, Warning{prev.start, n.end,
+						fmt.Sprintf("adjacent quantifiers in %s match the same characters and can backtrack polynomially", expr[prev.start:n.end])})
+				}
+			}
+
+			lintItems(expr, n.alternatives, warnings)
+		}
+	}
+}
+
+// overlappingAlternatives returns the source of two alternatives that can
+// start with the same character.
+func overlappingAlternatives(expr string, alternatives [][]*lintNode) (string, string, bool) {
+	for i, a := range alternatives {
+		for _, b := range alternatives[i+1:] {
+			if overlap(first(a), first(b)) {
+				return expr[a[0].start:a[len(a)-1].end], expr[b[0].start:b[len(b)-1].end], true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// first returns the source of the leftmost atom of sequence, or "" if it is
+// unknown.
+func first(sequence []*lintNode) string {
+	if len(sequence) == 0 {
+		return ""
+	}
+	if n := sequence[0]; n.text != "" {
+		return n.text
+	} else if len(n.alternatives) == 1 {
+		return first(n.alternatives[0])
+	}
+
+	return ""
+}
+
+var quantifierBounds = regexp.MustCompile(`^\{(\d+)(,(\d*))?\}`)
+
+// overlapSamples are the characters tried when checking whether two atoms
+// can match the same character.
+var overlapSamples = func() []string {
+	samples := []string{"\t", "\n", "é", "日"}
+	for c := ' '; c <= '~'; c++ {
+		samples = append(samples, string(c))
+	}
+
+	return samples
+}()
+
+// overlap reports whether the atoms a and b can match the same character.
+func overlap(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+
+	reA, errA := regexp.Compile(`^(?:` + a + `)$`)
+	reB, errB := regexp.Compile(`^(?:` + b + `)$`)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+
+	for _, sample := range overlapSamples {
+		if reA.MatchString(sample) && reB.MatchString(sample) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,30 @@
+package regex
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []Warning
+	}{
+		{`(a+)+$`, []Warning{{0, 5, "nested quantifiers in (a+)+ can backtrack exponentially"}}},
+		{`(\w+\s?)*`, []Warning{{0, 9, `nested quantifiers in (\w+\s?)* can backtrack exponentially`}}},
+		{`(a|a)*`, []Warning{{0, 6, "alternatives a and a of (a|a)* match the same characters and can backtrack exponentially"}}},
+		{`\d+\d+`, []Warning{{0, 6, `adjacent quantifiers in \d+\d+ match the same characters and can backtrack polynomially`}}},
+		{`(a|b)*`, nil},
+		{`(?>a+)+`, nil},
+		{`(a{2})+`, nil},
+		{`\w+\s\w+`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if got := Lint(tt.expr); !slices.Equal(got, tt.want) {
+				t.Errorf("Lint(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
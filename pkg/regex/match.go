@@ -0,0 +1,114 @@
+package regex
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Span is a half-open range of byte offsets [Start, End) in the input.
+type Span struct {
+	Start, End int
+}
+
+func (s Span) Len() int {
+	return s.End - s.Start
+}
+
+// Empty reports whether the span is zero-width.
+func (s Span) Empty() bool {
+	return s.Start == s.End
+}
+
+// Text returns the part of input covered by the span.
+func (s Span) Text(input string) string {
+	return input[s.Start:s.End]
+}
+
+// Group is a capture group within a match.
+type Group struct {
+	// Index is the slot of the group in the submatch indexes.
+	Index int
+	// Name is empty for unnamed groups.
+	Name string
+	Span
+	// Matched is false when the group did not participate in the match, in
+	// which case its span is {-1, -1}.
+	Matched bool
+}
+
+// Label returns the index of the group, followed by its name if it has one.
+func (g Group) Label() string {
+	if g.Name != "" {
+		return strconv.Itoa(g.Index) + " " + g.Name
+	}
+
+	return strconv.Itoa(g.Index)
+}
+
+// Capturer is implemented by engines that record every capture of a
+// repeated group, where submatch indexes only report the last one.
+type Capturer interface {
+	// Captures returns the spans captured by each group of the match with
+	// the given ordinal, in slot order and excluding the whole match.
+	Captures(s string, ordinal int) ([][]Span, error)
+}
+
+// Match is a single match of an expression in the input.
+type Match struct {
+	// Ordinal is the 1-based position of the match among all matches.
+	Ordinal int
+	Span
+	// Line and Column locate the start of the match, both 1-based. Columns
+	// count runes.
+	Line, Column int
+	Groups       []Group
+}
+
+// FindMatches returns up to n matches of regex in s, or all of them if n < 0.
+// It returns an empty slice when there is no match.
+func FindMatches(regex Regex, s string, n int) ([]Match, error) {
+	indexes, err := regex.FindAllStringSubmatchIndex(s, n)
+	if err != nil {
+		return nil, err
+	}
+
+	names := regex.SubexpNames()
+	matches := make([]Match, 0, len(indexes))
+	line, lineStart, lastIndex := 1, 0, 0
+
+	for i, index := range indexes {
+		start := index[0]
+		newlines := strings.Count(s[lastIndex:start], "\n")
+		if newlines > 0 {
+			line += newlines
+			lineStart = lastIndex + strings.LastIndexByte(s[lastIndex:start], '\n') + 1
+		}
+		lastIndex = start
+
+		match := Match{
+			Ordinal: i + 1,
+			Span:    Span{index[0], index[1]},
+			Line:    line,
+			Column:  utf8.RuneCountInString(s[lineStart:start]) + 1,
+			Groups:  make([]Group, 0, len(index)/2-1),
+		}
+
+		for slot := 1; slot < len(index)/2; slot++ {
+			group := Group{
+				Index:   slot,
+				Span:    Span{index[2*slot], index[2*slot+1]},
+				Matched: index[2*slot] >= 0,
+			}
+			if slot < len(names) {
+				group.Name = names[slot]
+			}
+
+			match.Groups = append(match.Groups, group)
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
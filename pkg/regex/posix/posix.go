@@ -0,0 +1,78 @@
+package posix
+
+import (
+	"regexp"
+	"regexp/syntax"
+
+	"github.com/vitor-mariano/regex-tui/pkg/regex"
+	"github.com/vitor-mariano/regex-tui/pkg/regex/explain"
+	"github.com/vitor-mariano/regex-tui/pkg/regex/re2"
+)
+
+// POSIXRegex matches with POSIX ERE syntax and leftmost-longest semantics,
+// as grep -E and awk do.
+type POSIXRegex struct {
+	*re2.RE2Regex
+}
+
+func init() {
+	regex.Register(regex.Engine{
+		Name:        "POSIX",
+		Description: "POSIX ERE syntax with leftmost-longest matching",
+		Flags:       regex.CommonFlags | regex.Ungreedy,
+		New: func(expr string, opts regex.Options) (regex.Regex, error) {
+			re, err := New(expr, opts)
+			if err != nil {
+				return nil, err
+			}
+			return re, nil
+		},
+		Explain: Explain,
+	})
+}
+
+// New compiles expr with the POSIX ERE syntax accepted by
+// regexp.CompilePOSIX. Flags are applied while parsing, as the syntax has no
+// inline flag groups.
+func New(expr string, opts regex.Options) (*POSIXRegex, error) {
+	parsed, err := syntax.Parse(expr, parseFlags(opts.Flags))
+	if err != nil {
+		return nil, re2.SyntaxError(err, "", expr)
+	}
+
+	re, err := regexp.Compile(parsed.String())
+	if err != nil {
+		return nil, err
+	}
+	re.Longest()
+
+	return &POSIXRegex{re2.FromRegexp(re)}, nil
+}
+
+// Explain describes expr as parsed with the POSIX ERE syntax.
+func Explain(expr string, flags regex.Flags) ([]*explain.Node, error) {
+	parsed, err := syntax.Parse(expr, parseFlags(flags))
+	if err != nil {
+		return nil, re2.SyntaxError(err, "", expr)
+	}
+
+	return explain.Syntax(parsed), nil
+}
+
+func parseFlags(flags regex.Flags) syntax.Flags {
+	parseFlags := syntax.POSIX
+	if !flags.Has(regex.Multiline) {
+		parseFlags |= syntax.OneLine
+	}
+	if flags.Has(regex.Insensitive) {
+		parseFlags |= syntax.FoldCase
+	}
+	if flags.Has(regex.DotAll) {
+		parseFlags |= syntax.DotNL
+	}
+	if flags.Has(regex.Ungreedy) {
+		parseFlags |= syntax.NonGreedy
+	}
+
+	return parseFlags
+}
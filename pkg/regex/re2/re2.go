@@ -1,26 +1,169 @@
 package re2
 
 import (
+	"errors"
 	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/vitor-mariano/regex-tui/pkg/regex"
+	"github.com/vitor-mariano/regex-tui/pkg/regex/explain"
 )
 
 type RE2Regex struct {
 	re *regexp.Regexp
 }
 
-func New(expr string) (*RE2Regex, error) {
-	re, err := regexp.Compile(expr)
+func init() {
+	regex.Register(regex.Engine{
+		Name:        "RE2",
+		Description: "Go regexp package, linear time matching",
+		Flags:       regex.CommonFlags | regex.Ungreedy,
+		Features:    regex.UnicodeClasses,
+		New: func(expr string, opts regex.Options) (regex.Regex, error) {
+			re, err := New(expr, opts)
+			if err != nil {
+				return nil, err
+			}
+			return re, nil
+		},
+		Explain: Explain,
+	})
+}
+
+// New compiles expr with the RE2 engine. Matching runs in linear time, so the
+// timeout is ignored.
+func New(expr string, opts regex.Options) (*RE2Regex, error) {
+	prefix := flagsPrefix(opts.Flags)
+	re, err := regexp.Compile(prefix + expr)
+	if err != nil {
+		return nil, SyntaxError(err, prefix, expr)
+	}
+
+	return FromRegexp(re), nil
+}
+
+// Explain describes expr as parsed by the regexp package.
+func Explain(expr string, flags regex.Flags) ([]*explain.Node, error) {
+	prefix := flagsPrefix(flags)
+	re, err := syntax.Parse(prefix+expr, syntax.Perl)
 	if err != nil {
-		return nil, err
+		return nil, SyntaxError(err, prefix, expr)
 	}
 
-	return &RE2Regex{re}, nil
+	return explain.Syntax(re), nil
 }
 
-func (regex *RE2Regex) FindAllStringIndex(s string, n int) [][]int {
-	return regex.re.FindAllStringIndex(s, n)
+// FromRegexp wraps a compiled regexp, letting engines built on the regexp
+// package share the RE2 implementation.
+func FromRegexp(re *regexp.Regexp) *RE2Regex {
+	return &RE2Regex{re}
 }
 
-func (regex *RE2Regex) FindStringIndex(s string) []int {
-	return regex.re.FindStringIndex(s)
+// SyntaxError converts a *syntax.Error returned while compiling prefix+expr
+// into a *regex.SyntaxError locating the offending part of expr. Unbalanced
+// brackets, which are reported for the rest of the expression, are located
+// by scanning it.
+func SyntaxError(err error, prefix, expr string) error {
+	var syntaxErr *syntax.Error
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	// The fragment is searched in the compiled source, so that offsets do
+	// not depend on the flags.
+	offset, fragment := -1, ""
+	source := prefix + expr
+	if i := strings.Index(source, syntaxErr.Expr); syntaxErr.Expr != "" && i >= 0 {
+		start := max(i, len(prefix))
+		offset, fragment = start-len(prefix), source[start:i+len(syntaxErr.Expr)]
+	}
+
+	// Errors about parentheses carry the rest of the expression.
+	unexpected, unclosed := regex.Unbalanced(expr)
+	switch syntaxErr.Code {
+	case syntax.ErrMissingParen:
+		offset, fragment = unclosed, ""
+	case syntax.ErrUnexpectedParen:
+		offset, fragment = unexpected, ""
+	case syntax.ErrMissingBracket:
+		offset = unclosed
+	case syntax.ErrTrailingBackslash:
+		offset = len(expr) - 1
+	}
+
+	message := syntaxErr.Code.String()
+	if fragment != "" {
+		message += ": `" + fragment + "`"
+	}
+
+	return &regex.SyntaxError{Message: message, Offset: offset, Err: err}
+}
+
+// flagsPrefix returns the inline flag group that enables flags, e.g. "(?is)".
+func flagsPrefix(flags regex.Flags) string {
+	letters := ""
+	if flags.Has(regex.Insensitive) {
+		letters += "i"
+	}
+	if flags.Has(regex.Multiline) {
+		letters += "m"
+	}
+	if flags.Has(regex.DotAll) {
+		letters += "s"
+	}
+	if flags.Has(regex.Ungreedy) {
+		letters += "U"
+	}
+
+	if letters == "" {
+		return ""
+	}
+
+	return "(?" + letters + ")"
+}
+
+func (regex *RE2Regex) FindAllStringIndex(s string, n int) ([][]int, error) {
+	return regex.re.FindAllStringIndex(s, n), nil
+}
+
+func (regex *RE2Regex) FindStringIndex(s string) ([]int, error) {
+	return regex.re.FindStringIndex(s), nil
+}
+
+func (regex *RE2Regex) FindAllStringSubmatchIndex(s string, n int) ([][]int, error) {
+	return regex.re.FindAllStringSubmatchIndex(s, n), nil
+}
+
+func (regex *RE2Regex) FindStringSubmatchIndex(s string) ([]int, error) {
+	return regex.re.FindStringSubmatchIndex(s), nil
+}
+
+func (regex *RE2Regex) NumSubexp() int {
+	return regex.re.NumSubexp()
+}
+
+func (regex *RE2Regex) SubexpNames() []string {
+	return regex.re.SubexpNames()
+}
+
+func (regex *RE2Regex) SubexpIndex(name string) int {
+	return regex.re.SubexpIndex(name)
+}
+
+func (regex *RE2Regex) ReplaceAllString(src, repl string, n int) (string, error) {
+	if n < 0 {
+		return regex.re.ReplaceAllString(src, repl), nil
+	}
+
+	var b []byte
+	lastIndex := 0
+	for _, match := range regex.re.FindAllStringSubmatchIndex(src, n) {
+		b = append(b, src[lastIndex:match[0]]...)
+		b = regex.re.ExpandString(b, repl, src, match)
+		lastIndex = match[1]
+	}
+	b = append(b, src[lastIndex:]...)
+
+	return string(b), nil
 }
@@ -0,0 +1,42 @@
+package re2
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vitor-mariano/regex-tui/pkg/regex"
+)
+
+func TestSyntaxErrorOffset(t *testing.T) {
+	tests := []struct {
+		expr   string
+		offset int
+	}{
+		{`[a`, 0},
+		{`ab[cd`, 2},
+		{`\p{Foo}`, 0},
+		{`x\p{Foo}`, 1},
+		{`(?<=a)b`, 0},
+		{`a(?<=b)`, 1},
+		{`a(b`, 1},
+		{`a)`, 1},
+		{`a**`, 1},
+		{`x{2,1}`, 1},
+		{`ab[z-a]`, 3},
+		{`ab\`, 2},
+	}
+
+	for _, tt := range tests {
+		for _, flags := range []regex.Flags{0, regex.Insensitive | regex.DotAll} {
+			_, err := New(tt.expr, regex.Options{Flags: flags})
+
+			var syntaxErr *regex.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("New(%q) error = %v, want a *regex.SyntaxError", tt.expr, err)
+			}
+			if syntaxErr.Offset != tt.offset {
+				t.Errorf("New(%q) with flags %v: offset = %d, want %d", tt.expr, flags, syntaxErr.Offset, tt.offset)
+			}
+		}
+	}
+}
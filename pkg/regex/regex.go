@@ -1,7 +1,43 @@
 package regex
 
+import "time"
+
 // Regex abstracts over compiled regular expressions for different engines.
+// All indexes are byte offsets into the input string, regardless of how the
+// engine counts internally. Matching methods only fail when the engine
+// aborts, e.g. with a *TimeoutError.
 type Regex interface {
-	FindAllStringIndex(s string, n int) [][]int
-	FindStringIndex(s string) []int
+	FindAllStringIndex(s string, n int) ([][]int, error)
+	FindStringIndex(s string) ([]int, error)
+
+	// FindAllStringSubmatchIndex and FindStringSubmatchIndex follow the
+	// semantics of their regexp counterparts: each match is a slice of
+	// 2*(groups+1) offsets, with -1 for groups that did not participate.
+	FindAllStringSubmatchIndex(s string, n int) ([][]int, error)
+	FindStringSubmatchIndex(s string) ([]int, error)
+
+	// NumSubexp returns the number of capture groups in the expression.
+	NumSubexp() int
+	// SubexpNames returns the names of the capture groups, aligned with the
+	// slots of the submatch indexes: names[0] is the whole match and unnamed
+	// groups have an empty name. Engines number mixed named and unnamed
+	// groups differently, so callers must always go through this slice
+	// rather than assume the pattern order.
+	SubexpNames() []string
+	// SubexpIndex returns the slot of the group with the given name, or -1
+	// if there is no such group.
+	SubexpIndex(name string) int
+
+	// ReplaceAllString replaces up to n matches in src with the expansion of
+	// repl, using the template syntax of the engine. If n < 0, all matches
+	// are replaced.
+	ReplaceAllString(src, repl string, n int) (string, error)
+}
+
+// Options configures how an expression is compiled and matched.
+type Options struct {
+	Flags Flags
+	// Timeout bounds the duration of a single match for backtracking
+	// engines. Zero disables it.
+	Timeout time.Duration
 }
@@ -1,47 +1,420 @@
 package regexp2
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/dlclark/regexp2"
+	"github.com/dlclark/regexp2/syntax"
+	"github.com/vitor-mariano/regex-tui/pkg/regex"
+	"github.com/vitor-mariano/regex-tui/pkg/regex/explain"
 )
 
 type Regexp2Regex struct {
-	re *regexp2.Regexp
+	re    *regexp2.Regexp
+	names []string
+}
+
+var ErrUngreedy = errors.New("the ungreedy flag is not supported by regexp2")
+
+func init() {
+	regex.Register(regex.Engine{
+		Name:        "Regexp2",
+		Description: "Backtracking engine with partial PCRE and .NET compatibility",
+		Flags: regex.Insensitive | regex.Multiline | regex.DotAll |
+			regex.RightToLeft | regex.ECMAScript | regex.ExplicitCapture |
+			regex.IgnorePatternWhitespace | regex.RE2Compat,
+		Features: regex.Lookahead | regex.Lookbehind | regex.Backreferences |
+			regex.AtomicGroups | regex.Conditionals | regex.UnicodeClasses,
+		Backtracking: true,
+		New: func(expr string, opts regex.Options) (regex.Regex, error) {
+			re, err := New(expr, opts)
+			if err != nil {
+				return nil, err
+			}
+			return re, nil
+		},
+		Explain: Explain,
+		Quote:   regexp2.Escape,
+	})
 }
 
-func New(expr string) (*Regexp2Regex, error) {
-	re, err := regexp2.Compile(expr, 0)
+// New compiles expr with the regexp2 engine. Matches taking longer than
+// opts.Timeout are aborted with a *regex.TimeoutError.
+func New(expr string, opts regex.Options) (*Regexp2Regex, error) {
+	compileOpts, err := compileOptions(opts.Flags)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp2.Compile(expr, compileOpts)
 	if err != nil {
+		return nil, syntaxError(err, expr)
+	}
+
+	if opts.Timeout > 0 {
+		re.MatchTimeout = opts.Timeout
+	}
+
+	return &Regexp2Regex{re, subexpNames(re)}, nil
+}
+
+// Explain describes expr with a parser following the syntax of regexp2. The
+// expression is compiled first, so that errors match the ones of New.
+func Explain(expr string, flags regex.Flags) ([]*explain.Node, error) {
+	if _, err := New(expr, regex.Options{Flags: flags}); err != nil {
 		return nil, err
 	}
 
-	return &Regexp2Regex{re}, nil
+	return explain.Parse(expr, explain.Options{
+		Insensitive:             flags.Has(regex.Insensitive),
+		Multiline:               flags.Has(regex.Multiline),
+		DotAll:                  flags.Has(regex.DotAll),
+		ExplicitCapture:         flags.Has(regex.ExplicitCapture),
+		IgnorePatternWhitespace: flags.Has(regex.IgnorePatternWhitespace),
+	})
 }
 
-func (regex *Regexp2Regex) FindAllStringIndex(s string, n int) [][]int {
-	var matches [][]int
-	match, err := regex.re.FindStringMatch(s)
-	if err != nil {
-		return matches
+// syntaxError converts a *syntax.Error, which always refers to the whole
+// expression, guessing the offending offset from its code and arguments.
+func syntaxError(err error, expr string) error {
+	var syntaxErr *syntax.Error
+	if !errors.As(err, &syntaxErr) {
+		return err
 	}
 
-	count := 0
-	for match != nil && (n < 0 || count < n) {
-		matches = append(matches, []int{match.Index, match.Index + match.Length})
-		match, err = regex.re.FindNextMatch(match)
-		if err != nil {
+	message := string(syntaxErr.Code)
+	if len(syntaxErr.Args) > 0 {
+		message = fmt.Sprintf(message, syntaxErr.Args...)
+	}
+
+	offset := -1
+	unexpected, unclosed := regex.Unbalanced(expr)
+	switch syntaxErr.Code {
+	case syntax.ErrMissingParen, syntax.ErrUnterminatedBracket:
+		offset = unclosed
+	case syntax.ErrUnexpectedParen:
+		offset = unexpected
+	case syntax.ErrIllegalEndEscape:
+		offset = len(expr) - 1
+	case syntax.ErrMalformedSlashP, syntax.ErrIncompleteSlashP, syntax.ErrUnknownSlashP:
+		offset = strings.Index(expr, `\p`)
+		if offset < 0 {
+			offset = strings.Index(expr, `\P`)
+		}
+	case syntax.ErrUnrecognizedEscape, syntax.ErrUndefinedBackRef, syntax.ErrBadClassInCharRange:
+		offset = strings.Index(expr, `\`+argString(syntaxErr.Args[0]))
+	case syntax.ErrUnrecognizedGrouping:
+		offset = strings.Index(expr, "("+argString(syntaxErr.Args[0]))
+	case syntax.ErrReversedCharRange:
+		offset = strings.Index(expr, argString(syntaxErr.Args[0])+"-"+argString(syntaxErr.Args[1]))
+	case syntax.ErrInvalidGroupName, syntax.ErrInvalidRepeatSize,
+		syntax.ErrMissingRepeatArgument, syntax.ErrInvalidRepeatOp:
+		offset = tokenOffset(syntaxErr.Code, expr)
+	default:
+		if len(syntaxErr.Args) > 0 {
+			offset = strings.Index(expr, argString(syntaxErr.Args[0]))
+		}
+	}
+
+	return &regex.SyntaxError{Message: message, Offset: offset, Err: err}
+}
+
+var (
+	repeatBounds = regexp.MustCompile(`^\{(\d+)(?:,(\d*))?\}`)
+	namedGroup   = regexp.MustCompile(`^\(\?(?:P?<|')([^>']*)`)
+	// groupName also accepts balancing groups such as (?<a-b>).
+	groupName = regexp.MustCompile(`^(\d+|[^\W\d]\w*)?(-(\d+|[^\W\d]\w*))?$`)
+)
+
+// tokenOffset locates errors about groups and quantifiers, which regexp2
+// reports without arguments, by looking for the first token causing them.
+func tokenOffset(code syntax.ErrorCode, expr string) int {
+	tokens := regex.Tokenize(expr)
+	for i, token := range tokens {
+		text := expr[token.Start:token.End]
+		switch code {
+		case syntax.ErrInvalidGroupName:
+			rest := expr[token.Start:]
+			if token.Kind != regex.TokenGroup || strings.HasPrefix(rest, "(?<=") || strings.HasPrefix(rest, "(?<!") {
+				continue
+			}
+			// Names without a terminator are not tokenized with the group.
+			name := namedGroup.FindStringSubmatch(rest)
+			if name != nil && (!strings.HasSuffix(text, ">") && !strings.HasSuffix(text, "'") ||
+				name[1] == "" || !groupName.MatchString(name[1])) {
+				return token.Start
+			}
+
+		case syntax.ErrInvalidRepeatSize:
+			if bounds := repeatBounds.FindStringSubmatch(text); bounds != nil && bounds[2] != "" {
+				lo, _ := strconv.Atoi(bounds[1])
+				hi, _ := strconv.Atoi(bounds[2])
+				if lo > hi {
+					return token.Start
+				}
+			}
+
+		case syntax.ErrMissingRepeatArgument:
+			if token.Kind != regex.TokenQuantifier {
+				continue
+			}
+			if i == 0 || tokens[i-1].Kind == regex.TokenAlternation ||
+				tokens[i-1].Kind == regex.TokenGroup && expr[tokens[i-1].End-1] != ')' {
+				return token.Start
+			}
+
+		case syntax.ErrInvalidRepeatOp:
+			if token.Kind != regex.TokenQuantifier {
+				continue
+			}
+			// regexp2 has no possessive quantifiers.
+			if strings.HasSuffix(text, "+") && len(text) > 1 {
+				return token.End - 1
+			}
+			if i > 0 && tokens[i-1].Kind == regex.TokenQuantifier {
+				return token.Start
+			}
+		}
+	}
+
+	return -1
+}
+
+func argString(arg any) string {
+	if r, ok := arg.(rune); ok {
+		return string(r)
+	}
+
+	return fmt.Sprint(arg)
+}
+
+func compileOptions(flags regex.Flags) (regexp2.RegexOptions, error) {
+	if flags.Has(regex.Ungreedy) {
+		return 0, ErrUngreedy
+	}
+
+	opts := regexp2.None
+	if flags.Has(regex.Insensitive) {
+		opts |= regexp2.IgnoreCase
+	}
+	if flags.Has(regex.Multiline) {
+		opts |= regexp2.Multiline
+	}
+	if flags.Has(regex.DotAll) {
+		opts |= regexp2.Singleline
+	}
+	if flags.Has(regex.RightToLeft) {
+		opts |= regexp2.RightToLeft
+	}
+	if flags.Has(regex.ECMAScript) {
+		opts |= regexp2.ECMAScript
+	}
+	if flags.Has(regex.ExplicitCapture) {
+		opts |= regexp2.ExplicitCapture
+	}
+	if flags.Has(regex.IgnorePatternWhitespace) {
+		opts |= regexp2.IgnorePatternWhitespace
+	}
+	if flags.Has(regex.RE2Compat) {
+		opts |= regexp2.RE2
+	}
+
+	return opts, nil
+}
+
+// subexpNames lists the group names in slot order. regexp2 numbers unnamed
+// groups before named ones and names unnamed groups after their number, so
+// those are reported as empty to match the regexp convention.
+func subexpNames(re *regexp2.Regexp) []string {
+	names := re.GetGroupNames()
+	for i, name := range names {
+		if _, err := strconv.Atoi(name); err == nil {
+			names[i] = ""
+		}
+	}
+
+	return names
+}
+
+// matchError converts the errors returned while matching started at start.
+// regexp2 only fails at match time when MatchTimeout is exceeded, but it does
+// so with a plain formatted error. Its deadline is checked against a clock
+// ticking every 100ms, so the elapsed time is measured here.
+func matchError(re *regexp2.Regexp, err error, start time.Time) error {
+	if err != nil && strings.HasPrefix(err.Error(), "match timeout") {
+		return &regex.TimeoutError{Timeout: re.MatchTimeout, Elapsed: time.Since(start)}
+	}
+
+	return err
+}
+
+// byteOffsets maps the rune indexes reported by regexp2 to byte offsets in
+// s, including the offset past the last rune. Invalid UTF-8 bytes count as
+// one rune each, as in regexp2's own conversion.
+func byteOffsets(s string) []int {
+	offsets := make([]int, 0, len(s)+1)
+	for i := range s {
+		offsets = append(offsets, i)
+	}
+
+	return append(offsets, len(s))
+}
+
+func findAll[T any](regex *Regexp2Regex, s string, n int, index func(*regexp2.Match, []int) T) ([]T, error) {
+	var matches []T
+	start := time.Now()
+	runes := []rune(s)
+	match, err := regex.re.FindRunesMatch(runes)
+	offsets := byteOffsets(s)
+
+	for err == nil && match != nil && (n < 0 || len(matches) < n) {
+		matches = append(matches, index(match, offsets))
+
+		var next *regexp2.Match
+		next, err = regex.nextMatch(match, runes)
+		if next != nil && next.Index == match.Index && next.Length == match.Length {
 			break
 		}
-		count++
+		match = next
+	}
+
+	if err != nil {
+		return nil, matchError(regex.re, err, start)
+	}
+
+	// Right-to-left expressions find matches from the end of the input.
+	if regex.re.RightToLeft() {
+		slices.Reverse(matches)
+	}
+
+	return matches, nil
+}
+
+// nextMatch returns the match following match in runes. FindNextMatch
+// stops after an empty match at the end of the input and, when matching
+// right to left, repeats an empty match at its start, so the search resumes
+// past empty right-to-left matches here instead.
+func (regex *Regexp2Regex) nextMatch(match *regexp2.Match, runes []rune) (*regexp2.Match, error) {
+	if !regex.re.RightToLeft() || match.Length > 0 {
+		return regex.re.FindNextMatch(match)
+	}
+
+	if match.Index == 0 {
+		return nil, nil
 	}
 
-	return matches
+	return regex.re.FindRunesMatchStartingAt(runes, match.Index-1)
 }
 
-func (regex *Regexp2Regex) FindStringIndex(s string) []int {
+func (regex *Regexp2Regex) find(s string, index func(*regexp2.Match, []int) []int) ([]int, error) {
+	start := time.Now()
 	match, err := regex.re.FindStringMatch(s)
-	if err != nil || match == nil {
-		return nil
+	if err != nil {
+		return nil, matchError(regex.re, err, start)
+	}
+
+	if match == nil {
+		return nil, nil
+	}
+
+	return index(match, byteOffsets(s)), nil
+}
+
+func (regex *Regexp2Regex) FindAllStringIndex(s string, n int) ([][]int, error) {
+	return findAll(regex, s, n, matchIndex)
+}
+
+func (regex *Regexp2Regex) FindStringIndex(s string) ([]int, error) {
+	return regex.find(s, matchIndex)
+}
+
+func (regex *Regexp2Regex) FindAllStringSubmatchIndex(s string, n int) ([][]int, error) {
+	return findAll(regex, s, n, submatchIndex)
+}
+
+func (regex *Regexp2Regex) FindStringSubmatchIndex(s string) ([]int, error) {
+	return regex.find(s, submatchIndex)
+}
+
+func matchIndex(match *regexp2.Match, offsets []int) []int {
+	return []int{offsets[match.Index], offsets[match.Index+match.Length]}
+}
+
+func submatchIndex(match *regexp2.Match, offsets []int) []int {
+	groups := match.Groups()
+	index := make([]int, 0, len(groups)*2)
+	for _, group := range groups {
+		if len(group.Captures) == 0 {
+			index = append(index, -1, -1)
+			continue
+		}
+
+		index = append(index, offsets[group.Index], offsets[group.Index+group.Length])
+	}
+
+	return index
+}
+
+// Captures returns every capture of each group of the match with the given
+// ordinal, which regexp2 records for repeated groups.
+func (regex *Regexp2Regex) Captures(s string, ordinal int) ([][]regex.Span, error) {
+	// Right-to-left matches are reported in reverse, so all of them are
+	// needed to find the one with the ordinal.
+	n := ordinal
+	if regex.re.RightToLeft() {
+		n = -1
+	}
+
+	matches, err := findAll(regex, s, n, captures)
+	if err != nil || ordinal < 1 || ordinal > len(matches) {
+		return nil, err
+	}
+
+	return matches[ordinal-1], nil
+}
+
+func captures(match *regexp2.Match, offsets []int) [][]regex.Span {
+	groups := match.Groups()[1:]
+	spans := make([][]regex.Span, len(groups))
+	for i, group := range groups {
+		for _, c := range group.Captures {
+			spans[i] = append(spans[i], regex.Span{Start: offsets[c.Index], End: offsets[c.Index+c.Length]})
+		}
 	}
 
-	return []int{match.Index, match.Index + match.Length}
+	return spans
+}
+
+func (regex *Regexp2Regex) NumSubexp() int {
+	return len(regex.names) - 1
+}
+
+func (regex *Regexp2Regex) SubexpNames() []string {
+	return regex.names
+}
+
+func (regex *Regexp2Regex) SubexpIndex(name string) int {
+	if name == "" {
+		return -1
+	}
+
+	for i, n := range regex.names {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (regex *Regexp2Regex) ReplaceAllString(src, repl string, n int) (string, error) {
+	start := time.Now()
+	s, err := regex.re.Replace(src, repl, -1, n)
+	return s, matchError(regex.re, err, start)
 }
@@ -0,0 +1,130 @@
+package regexp2
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitor-mariano/regex-tui/pkg/regex"
+	"github.com/vitor-mariano/regex-tui/pkg/regex/re2"
+)
+
+func TestFindAllStringIndexRightToLeft(t *testing.T) {
+	tests := []struct {
+		expr    string
+		subject string
+		want    [][]int
+	}{
+		{`^`, "ab", [][]int{{0, 0}}},
+		{`^|\:`, "ab", [][]int{{0, 0}}},
+		{`\b`, "ab cd", [][]int{{0, 0}, {2, 2}, {3, 3}, {5, 5}}},
+		{`x*`, "ab", [][]int{{0, 0}, {1, 1}, {2, 2}}},
+		{`b`, "abab", [][]int{{1, 2}, {3, 4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			re, err := New(tt.expr, regex.Options{Flags: regex.RightToLeft})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := re.FindAllStringIndex(tt.subject, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAllStringIndex(%q) = %v, want %v", tt.subject, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindAllStringSubmatchIndexByteOffsets(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		subject string
+	}{
+		{"emoji", `(\S+)\s?`, "👍🏽 ok 😀!"},
+		{"emoji runes", `.`, "a👨‍👩‍👧b"},
+		{"combining marks", `(e\x{301})|(.)`, "cafe\u0301 ne\u0301e"},
+		{"cjk", `(本+)(語)?`, "日本語 本本 テキスト"},
+		{"cjk runes", `(.)(.)`, "漢字かな"},
+		{"invalid utf-8", `(.)b`, "a\xffb\xc3b\xe6\x97"},
+		{"invalid utf-8 before multibyte", `.`, "\xff日\xfe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := re2.New(tt.expr, regex.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			re, err := New(tt.expr, regex.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wantIndex, _ := want.FindAllStringSubmatchIndex(tt.subject, -1)
+			got, err := re.FindAllStringSubmatchIndex(tt.subject, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, wantIndex) {
+				t.Errorf("FindAllStringSubmatchIndex(%q) = %v, want %v", tt.subject, got, wantIndex)
+			}
+		})
+	}
+}
+
+func TestSyntaxErrorOffset(t *testing.T) {
+	tests := []struct {
+		expr   string
+		offset int
+	}{
+		{`[a`, 0},
+		{`a(b`, 1},
+		{`a)`, 1},
+		{`ab\`, 2},
+		{`x\p{Foo}`, 1},
+		{`ab[z-a]`, 3},
+		{`(?<x)`, 0},
+		{`a(?'1x'b)`, 1},
+		{`a{2,1}`, 1},
+		{`**`, 0},
+		{`a|*`, 2},
+		{`a**`, 2},
+		{`x+*`, 2},
+	}
+
+	for _, tt := range tests {
+		_, err := New(tt.expr, regex.Options{})
+
+		var syntaxErr *regex.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("New(%q) error = %v, want a *regex.SyntaxError", tt.expr, err)
+		}
+		if syntaxErr.Offset != tt.offset {
+			t.Errorf("New(%q): offset = %d, want %d", tt.expr, syntaxErr.Offset, tt.offset)
+		}
+	}
+}
+
+func TestTimeoutReportsElapsedTime(t *testing.T) {
+	re, err := New(`(a+)+$`, regex.Options{Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = re.FindAllStringIndex(strings.Repeat("a", 40)+"b", -1)
+	var timeoutErr *regex.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("FindAllStringIndex error = %v, want a *regex.TimeoutError", err)
+	}
+	if timeoutErr.Elapsed < timeoutErr.Timeout {
+		t.Errorf("Elapsed = %v, want at least the %v timeout", timeoutErr.Elapsed, timeoutErr.Timeout)
+	}
+}
@@ -0,0 +1,158 @@
+package regex
+
+import (
+	"regexp"
+	"unicode/utf8"
+)
+
+// TokenKind classifies the tokens of an expression for highlighting.
+type TokenKind int
+
+const (
+	TokenLiteral TokenKind = iota
+	TokenGroup
+	TokenAlternation
+	TokenClass
+	TokenQuantifier
+	TokenAnchor
+	TokenEscape
+	TokenComment
+)
+
+// Token is a span of an expression. Brackets opening or closing a group or
+// a character class refer to their counterpart with Pair, the index of its
+// token, which is -1 for unbalanced brackets and other tokens.
+type Token struct {
+	Kind       TokenKind
+	Start, End int
+	Pair       int
+}
+
+var (
+	groupPrefix   = regexp.MustCompile(`^\((\?(P?<[\w-]*>|'[\w-]*'|<[=!]|[:=!>|]|[A-Za-z-]*[:)]|#[^)]*\)|))?`)
+	longEscape    = regexp.MustCompile(`^\\([pPx]\{[^}]*\}?|[pP][A-Za-z]|x[0-9A-Fa-f]{0,2}|u[0-9A-Fa-f]{0,4}|c.|k<[^>]*>?|k'[^']*'?|[1-9][0-9]*)`)
+	quantifierEnd = regexp.MustCompile(`^[?+]`)
+	posixClass    = regexp.MustCompile(`^\[:\^?[a-z]+:\]`)
+)
+
+// Tokenize splits expr into tokens covering all of it. It follows the syntax
+// shared by the engines and never fails, so it can highlight an incomplete
+// expression.
+func Tokenize(expr string) []Token {
+	var (
+		tokens []Token
+		groups []int
+		class  = -1
+	)
+
+	add := func(kind TokenKind, start, end int) {
+		tokens = append(tokens, Token{Kind: kind, Start: start, End: end, Pair: -1})
+	}
+	pair := func(open int) {
+		close := len(tokens) - 1
+		tokens[open].Pair = close
+		tokens[close].Pair = open
+	}
+
+	for i := 0; i < len(expr); {
+		rest := expr[i:]
+		c := expr[i]
+		_, size := utf8.DecodeRuneInString(rest)
+		end := i + size
+
+		switch {
+		case c == '\\':
+			end = i + 1
+			if loc := longEscape.FindStringIndex(rest); loc != nil {
+				end = i + loc[1]
+			} else if i+1 < len(expr) {
+				_, size := utf8.DecodeRuneInString(expr[i+1:])
+				end = i + 1 + size
+			}
+
+			kind := TokenEscape
+			if class < 0 && end == i+2 && isAnchorEscape(expr[i+1]) {
+				kind = TokenAnchor
+			}
+			add(kind, i, end)
+
+		case class >= 0:
+			if loc := posixClass.FindStringIndex(rest); loc != nil {
+				end = i + loc[1]
+			}
+			add(TokenClass, i, end)
+			if c == ']' {
+				pair(class)
+				class = -1
+			}
+
+		case c == '[':
+			end = i + 1
+			if end < len(expr) && expr[end] == '^' {
+				end++
+			}
+			add(TokenClass, i, end)
+			class = len(tokens) - 1
+			// A leading ']' is a literal member of the class.
+			if end < len(expr) && expr[end] == ']' {
+				add(TokenClass, end, end+1)
+				end++
+			}
+
+		case c == '(':
+			loc := groupPrefix.FindStringIndex(rest)
+			end = i + loc[1]
+			switch last := expr[end-1]; {
+			case end > i+2 && expr[i+2] == '#':
+				add(TokenComment, i, end)
+			case end > i+2 && last == ')':
+				// Inline flags such as (?i) do not open a group.
+				add(TokenGroup, i, end)
+			default:
+				add(TokenGroup, i, end)
+				groups = append(groups, len(tokens)-1)
+			}
+
+		case c == ')':
+			add(TokenGroup, i, end)
+			if len(groups) > 0 {
+				pair(groups[len(groups)-1])
+				groups = groups[:len(groups)-1]
+			}
+
+		case c == '|':
+			add(TokenAlternation, i, end)
+
+		case c == '*' || c == '+' || c == '?' || c == '{' && countedQuantifier.MatchString(rest):
+			if c == '{' {
+				end = i + len(countedQuantifier.FindString(rest))
+			}
+			if quantifierEnd.MatchString(expr[end:]) {
+				end++
+			}
+			add(TokenQuantifier, i, end)
+
+		case c == '^' || c == '$':
+			add(TokenAnchor, i, end)
+
+		case c == '.':
+			add(TokenClass, i, end)
+
+		default:
+			add(TokenLiteral, i, end)
+		}
+
+		i = end
+	}
+
+	return tokens
+}
+
+func isAnchorEscape(c byte) bool {
+	switch c {
+	case 'b', 'B', 'A', 'z', 'Z', 'G':
+		return true
+	}
+
+	return false
+}
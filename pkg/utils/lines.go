@@ -0,0 +1,15 @@
+package utils
+
+// ClipLines keeps the first height lines, replacing the last one kept with
+// "…" when lines are cut. A single row shows the first line instead, since
+// the ellipsis alone would say nothing.
+func ClipLines(lines []string, height int) []string {
+	switch {
+	case len(lines) <= height:
+		return lines
+	case height <= 1:
+		return lines[:1]
+	default:
+		return append(lines[:height-1], "…")
+	}
+}